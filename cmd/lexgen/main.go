@@ -0,0 +1,672 @@
+// Lexgen compiles the token specification of the AtomC lexer into the tables
+// of a minimized DFA, which getNextToken runs.
+//
+//	go run ./cmd/lexgen -o lexer_table.go lexer.spec
+//
+// The format of the specification is described at the top of lexer.spec.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type rule struct {
+	kind string // token, skip or error
+	name string
+	re   string
+	line int
+}
+
+var ruleKinds = map[string]string{
+	"token": "lexToken",
+	"skip":  "lexSkip",
+	"error": "lexError",
+}
+
+func main() {
+	out := flag.String("o", "lexer_table.go", "output file")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "usage: lexgen [-o file] spec\n")
+		os.Exit(2)
+	}
+	specFile := flag.Arg(0)
+
+	rules, err := readSpec(specFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var n nfa
+	start := n.newState()
+	for i, r := range rules {
+		p := parser{re: r.re}
+		node, err := p.parse()
+		if err != nil {
+			log.Fatalf("%s:%d: %s: %v", specFile, r.line, r.name, err)
+		}
+		frag := n.build(node)
+		n.states[frag.end].rule = i
+		n.addEps(start, frag.start)
+	}
+
+	d := determinize(&n, start)
+	d = d.minimize()
+
+	src := emit(specFile, rules, d)
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("formatting the generated code: %v", err)
+	}
+	if err := ioutil.WriteFile(*out, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readSpec(file string) ([]rule, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("%s:%d: expected `kind name regex`", file, line)
+		}
+		if _, ok := ruleKinds[fields[0]]; !ok {
+			return nil, fmt.Errorf("%s:%d: unknown rule kind %q", file, line, fields[0])
+		}
+		// the regex is the rest of the line, so it may contain spaces
+		re := strings.TrimSpace(text[len(fields[0]):])
+		re = strings.TrimSpace(re[len(fields[1]):])
+		rules = append(rules, rule{kind: fields[0], name: fields[1], re: re, line: line})
+	}
+	return rules, scanner.Err()
+}
+
+// ---------------------- REGEX --------------------------------------
+
+type byteSet [4]uint64
+
+func (s *byteSet) add(b byte) {
+	s[b/64] |= 1 << (b % 64)
+}
+
+func (s *byteSet) has(b byte) bool {
+	return s[b/64]&(1<<(b%64)) != 0
+}
+
+func (s *byteSet) invert() {
+	for i := range s {
+		s[i] = ^s[i]
+	}
+}
+
+type nodeKind int
+
+const (
+	nodeSet nodeKind = iota
+	nodeCat
+	nodeAlt
+	nodeStar
+	nodePlus
+	nodeOpt
+)
+
+type node struct {
+	kind nodeKind
+	set  byteSet // for nodeSet
+	subs []*node
+}
+
+// a recursive descent parser for the regular expressions of the spec
+//
+//	alt:    cat ( '|' cat )*
+//	cat:    repeat*
+//	repeat: atom ( '*' | '+' | '?' )*
+//	atom:   '(' alt ')' | '[' '^'? class ']' | '.' | '\' char | char
+type parser struct {
+	re  string
+	pos int
+}
+
+func (p *parser) parse() (*node, error) {
+	n, err := p.alt()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.re) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.re[p.pos], p.pos)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() (byte, bool) {
+	if p.pos < len(p.re) {
+		return p.re[p.pos], true
+	}
+	return 0, false
+}
+
+func (p *parser) alt() (*node, error) {
+	first, err := p.cat()
+	if err != nil {
+		return nil, err
+	}
+	subs := []*node{first}
+	for {
+		c, ok := p.peek()
+		if !ok || c != '|' {
+			break
+		}
+		p.pos++
+		n, err := p.cat()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, n)
+	}
+	if len(subs) == 1 {
+		return first, nil
+	}
+	return &node{kind: nodeAlt, subs: subs}, nil
+}
+
+func (p *parser) cat() (*node, error) {
+	var subs []*node
+	for {
+		c, ok := p.peek()
+		if !ok || c == '|' || c == ')' {
+			break
+		}
+		n, err := p.repeat()
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, n)
+	}
+	if len(subs) == 0 {
+		return nil, fmt.Errorf("empty expression at offset %d", p.pos)
+	}
+	if len(subs) == 1 {
+		return subs[0], nil
+	}
+	return &node{kind: nodeCat, subs: subs}, nil
+}
+
+func (p *parser) repeat() (*node, error) {
+	n, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		c, ok := p.peek()
+		if !ok {
+			return n, nil
+		}
+		switch c {
+		case '*':
+			n = &node{kind: nodeStar, subs: []*node{n}}
+		case '+':
+			n = &node{kind: nodePlus, subs: []*node{n}}
+		case '?':
+			n = &node{kind: nodeOpt, subs: []*node{n}}
+		default:
+			return n, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *parser) atom() (*node, error) {
+	c, _ := p.peek()
+	p.pos++
+	switch c {
+	case '(':
+		n, err := p.alt()
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := p.peek(); !ok || c != ')' {
+			return nil, fmt.Errorf("missing `)`")
+		}
+		p.pos++
+		return n, nil
+	case '[':
+		return p.class()
+	case '.':
+		n := &node{kind: nodeSet}
+		n.set.add('\n')
+		n.set.invert()
+		return n, nil
+	case '*', '+', '?':
+		return nil, fmt.Errorf("nothing to repeat at offset %d", p.pos-1)
+	}
+	p.pos--
+	b, err := p.char()
+	if err != nil {
+		return nil, err
+	}
+	n := &node{kind: nodeSet}
+	n.set.add(b)
+	return n, nil
+}
+
+// a possibly escaped character
+func (p *parser) char() (byte, error) {
+	c, ok := p.peek()
+	if !ok {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+	if c != '\\' {
+		return c, nil
+	}
+	c, ok = p.peek()
+	if !ok {
+		return 0, fmt.Errorf("trailing `\\`")
+	}
+	p.pos++
+	switch c {
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '0':
+		return 0, nil
+	}
+	return c, nil
+}
+
+func (p *parser) class() (*node, error) {
+	n := &node{kind: nodeSet}
+	negate := false
+	if c, ok := p.peek(); ok && c == '^' {
+		negate = true
+		p.pos++
+	}
+	for {
+		c, ok := p.peek()
+		if !ok {
+			return nil, fmt.Errorf("missing `]`")
+		}
+		if c == ']' {
+			p.pos++
+			break
+		}
+		lo, err := p.char()
+		if err != nil {
+			return nil, err
+		}
+		hi := lo
+		if c, ok := p.peek(); ok && c == '-' && p.pos+1 < len(p.re) && p.re[p.pos+1] != ']' {
+			p.pos++
+			if hi, err = p.char(); err != nil {
+				return nil, err
+			}
+			if hi < lo {
+				return nil, fmt.Errorf("invalid range %q-%q", lo, hi)
+			}
+		}
+		for b := int(lo); b <= int(hi); b++ {
+			n.set.add(byte(b))
+		}
+	}
+	if negate {
+		n.set.invert()
+	}
+	return n, nil
+}
+
+// ---------------------- NFA ----------------------------------------
+
+type nfaState struct {
+	eps  []int
+	set  *byteSet // the state moves to next on the bytes of set
+	next int
+	rule int // the rule accepted in this state, -1 if none
+}
+
+type nfa struct {
+	states []nfaState
+}
+
+type fragment struct {
+	start, end int
+}
+
+func (n *nfa) newState() int {
+	n.states = append(n.states, nfaState{rule: -1})
+	return len(n.states) - 1
+}
+
+func (n *nfa) addEps(from, to int) {
+	n.states[from].eps = append(n.states[from].eps, to)
+}
+
+// Thompson's construction
+func (n *nfa) build(nd *node) fragment {
+	switch nd.kind {
+	case nodeSet:
+		f := fragment{n.newState(), n.newState()}
+		set := nd.set
+		n.states[f.start].set = &set
+		n.states[f.start].next = f.end
+		return f
+	case nodeCat:
+		f := n.build(nd.subs[0])
+		for _, sub := range nd.subs[1:] {
+			g := n.build(sub)
+			n.addEps(f.end, g.start)
+			f.end = g.end
+		}
+		return f
+	case nodeAlt:
+		f := fragment{n.newState(), n.newState()}
+		for _, sub := range nd.subs {
+			g := n.build(sub)
+			n.addEps(f.start, g.start)
+			n.addEps(g.end, f.end)
+		}
+		return f
+	}
+	// nodeStar, nodePlus, nodeOpt
+	f := fragment{n.newState(), n.newState()}
+	g := n.build(nd.subs[0])
+	n.addEps(f.start, g.start)
+	n.addEps(g.end, f.end)
+	if nd.kind != nodePlus {
+		n.addEps(f.start, f.end)
+	}
+	if nd.kind != nodeOpt {
+		n.addEps(g.end, g.start)
+	}
+	return f
+}
+
+// ---------------------- DFA ----------------------------------------
+
+type dfa struct {
+	class    [256]int // the equivalence class of each byte
+	nClasses int
+	trans    [][]int // trans[state][class], -1 for the dead state
+	accept   []int   // the rule accepted in each state, -1 if none
+}
+
+// partitions the bytes into classes which no transition of the NFA tells apart
+func byteClasses(n *nfa) ([256]int, int) {
+	var sets []*byteSet
+	for i := range n.states {
+		if n.states[i].set != nil {
+			sets = append(sets, n.states[i].set)
+		}
+	}
+	var class [256]int
+	ids := map[string]int{}
+	for b := 0; b < 256; b++ {
+		sig := make([]byte, len(sets))
+		for i, s := range sets {
+			if s.has(byte(b)) {
+				sig[i] = 1
+			}
+		}
+		id, ok := ids[string(sig)]
+		if !ok {
+			id = len(ids)
+			ids[string(sig)] = id
+		}
+		class[b] = id
+	}
+	return class, len(ids)
+}
+
+func closure(n *nfa, states []int) []int {
+	seen := map[int]bool{}
+	stack := append([]int(nil), states...)
+	for _, s := range states {
+		seen[s] = true
+	}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, t := range n.states[s].eps {
+			if !seen[t] {
+				seen[t] = true
+				stack = append(stack, t)
+			}
+		}
+	}
+	res := make([]int, 0, len(seen))
+	for s := range seen {
+		res = append(res, s)
+	}
+	sort.Ints(res)
+	return res
+}
+
+func key(states []int) string {
+	var b strings.Builder
+	for _, s := range states {
+		fmt.Fprintf(&b, "%d,", s)
+	}
+	return b.String()
+}
+
+// the subset construction; the start state of the result is 0
+func determinize(n *nfa, start int) *dfa {
+	d := &dfa{}
+	d.class, d.nClasses = byteClasses(n)
+	repr := make([]byte, d.nClasses)
+	for b := 255; b >= 0; b-- {
+		repr[d.class[b]] = byte(b)
+	}
+
+	var sets [][]int
+	ids := map[string]int{}
+	add := func(states []int) int {
+		k := key(states)
+		if id, ok := ids[k]; ok {
+			return id
+		}
+		ids[k] = len(sets)
+		sets = append(sets, states)
+		acc := -1
+		for _, s := range states {
+			if r := n.states[s].rule; r >= 0 && (acc < 0 || r < acc) {
+				acc = r
+			}
+		}
+		d.accept = append(d.accept, acc)
+		d.trans = append(d.trans, nil)
+		return len(sets) - 1
+	}
+	add(closure(n, []int{start}))
+	for i := 0; i < len(sets); i++ {
+		row := make([]int, d.nClasses)
+		for c := 0; c < d.nClasses; c++ {
+			var next []int
+			for _, s := range sets[i] {
+				st := n.states[s]
+				if st.set != nil && st.set.has(repr[c]) {
+					next = append(next, st.next)
+				}
+			}
+			if len(next) == 0 {
+				row[c] = -1
+			} else {
+				row[c] = add(closure(n, next))
+			}
+		}
+		d.trans[i] = row
+	}
+	return d
+}
+
+// merges equivalent states by partition refinement and drops the states from
+// which no rule can be accepted, so the lexer stops as soon as possible
+func (d *dfa) minimize() *dfa {
+	n := len(d.trans)
+
+	// the live states can reach an accepting state
+	live := make([]bool, n)
+	for changed := true; changed; {
+		changed = false
+		for s := 0; s < n; s++ {
+			if live[s] {
+				continue
+			}
+			if d.accept[s] >= 0 {
+				live[s] = true
+				changed = true
+				continue
+			}
+			for _, t := range d.trans[s] {
+				if t >= 0 && live[t] {
+					live[s] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	target := func(s, c int) int {
+		t := d.trans[s][c]
+		if t < 0 || !live[t] {
+			return -1
+		}
+		return t
+	}
+
+	block := make([]int, n)
+	for s := 0; s < n; s++ {
+		block[s] = d.accept[s]
+	}
+	nBlocks := -1
+	for {
+		ids := map[string]int{}
+		next := make([]int, n)
+		for s := 0; s < n; s++ {
+			var b strings.Builder
+			fmt.Fprintf(&b, "%d:", block[s])
+			for c := 0; c < d.nClasses; c++ {
+				t := target(s, c)
+				if t >= 0 {
+					t = block[t]
+				}
+				fmt.Fprintf(&b, "%d,", t)
+			}
+			id, ok := ids[b.String()]
+			if !ok {
+				id = len(ids)
+				ids[b.String()] = id
+			}
+			next[s] = id
+		}
+		block = next
+		if len(ids) == nBlocks {
+			break
+		}
+		nBlocks = len(ids)
+	}
+
+	// number the blocks in the order they are reached from the start state
+	m := &dfa{class: d.class, nClasses: d.nClasses}
+	num := map[int]int{block[0]: 0}
+	order := []int{0}
+	for i := 0; i < len(order); i++ {
+		s := order[i]
+		row := make([]int, d.nClasses)
+		for c := 0; c < d.nClasses; c++ {
+			t := target(s, c)
+			if t < 0 {
+				row[c] = -1
+				continue
+			}
+			id, ok := num[block[t]]
+			if !ok {
+				id = len(order)
+				num[block[t]] = id
+				order = append(order, t)
+			}
+			row[c] = id
+		}
+		m.trans = append(m.trans, row)
+		m.accept = append(m.accept, d.accept[s])
+	}
+	return m
+}
+
+// ---------------------- OUTPUT -------------------------------------
+
+func emit(specFile string, rules []rule, d *dfa) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by lexgen from %s; DO NOT EDIT.\n\n", specFile)
+	fmt.Fprintf(&b, "package main\n\n")
+
+	fmt.Fprintf(&b, "// the number of byte classes, the columns of lexTrans\n")
+	fmt.Fprintf(&b, "const lexNumClasses = %d\n\n", d.nClasses)
+
+	fmt.Fprintf(&b, "// the class of each input byte\n")
+	fmt.Fprintf(&b, "var lexClass = [256]uint8{")
+	for i, c := range d.class {
+		if i%16 == 0 {
+			fmt.Fprintf(&b, "\n")
+		}
+		fmt.Fprintf(&b, "%d, ", c)
+	}
+	fmt.Fprintf(&b, "\n}\n\n")
+
+	fmt.Fprintf(&b, "// lexTrans[state*lexNumClasses+class] is the next state of the DFA, -1\n")
+	fmt.Fprintf(&b, "// when no rule can match anymore; the start state is 0\n")
+	fmt.Fprintf(&b, "var lexTrans = [...]int16{\n")
+	for s, row := range d.trans {
+		fmt.Fprintf(&b, "\t// %d\n\t", s)
+		for _, t := range row {
+			fmt.Fprintf(&b, "%d, ", t)
+		}
+		fmt.Fprintf(&b, "\n")
+	}
+	fmt.Fprintf(&b, "}\n\n")
+
+	fmt.Fprintf(&b, "// the index in lexRules of the rule matched in each state, -1 if none\n")
+	fmt.Fprintf(&b, "var lexAccept = [...]int16{")
+	for i, a := range d.accept {
+		if i%16 == 0 {
+			fmt.Fprintf(&b, "\n")
+		}
+		fmt.Fprintf(&b, "%d, ", a)
+	}
+	fmt.Fprintf(&b, "\n}\n\n")
+
+	fmt.Fprintf(&b, "var lexRules = [...]lexRule{\n")
+	for _, r := range rules {
+		if r.kind == "token" {
+			fmt.Fprintf(&b, "\t{kind: %s, tokenType: %s, name: %q},\n", ruleKinds[r.kind], r.name, r.name)
+		} else {
+			fmt.Fprintf(&b, "\t{kind: %s, name: %q},\n", ruleKinds[r.kind], r.name)
+		}
+	}
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
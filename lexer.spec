@@ -0,0 +1,85 @@
+# The tokens of AtomC, compiled by cmd/lexgen into lexer_table.go:
+#
+#	go generate
+#
+# Each rule is `kind name regex`, the regex being the rest of the line. The
+# lexer always takes the longest match, and between rules matching the same
+# text the first one wins, so the keywords come before Id.
+#
+#	token	the text is returned as a token of the TokenType called name
+#	skip	the text is dropped, name only documents it
+#	error	the text is a common mistake; Lexer.recoverToken reports the
+#		error called name and returns the token that was likely meant
+#
+# The regex syntax: characters stand for themselves, `\` escapes an operator
+# or gives \n \r \t \0, [a-z0-9_] and [^"\\] are classes of bytes, `.` is any
+# byte but a newline, ( ) groups, | separates alternatives, and * + ? repeat.
+
+skip	Space		[ \t\r\n]+
+# the NUL and control characters in comments are reported by the lexer
+skip	LineComment	//[^\r\n]*
+skip	BlockComment	/\*([^*]|\*+[^*/])*\*+/
+
+token	Break		break
+token	Char		char
+token	Double		double
+token	Else		else
+token	Enum		enum
+token	For		for
+token	If		if
+token	Int		int
+token	Return		return
+token	Struct		struct
+token	Typedef		typedef
+token	Void		void
+token	While		while
+token	Id		[a-zA-Z_][a-zA-Z0-9_]*
+
+# decimal, octal and hex; 08 and 09 are only the start of a real
+token	CtInt		[1-9][0-9]*|0[0-7]*|0x[0-9a-fA-F]+
+token	CtReal		[0-9]+\.[0-9]+([eE][+-]?[0-9]+)?|[0-9]+[eE][+-]?[0-9]+
+# the escape sequences and the length of a char are checked when the value
+# is decoded; the byte classes also match the bytes of UTF-8 characters, which
+# are decoded then too
+token	CtChar		'([^'\\\n]|\\[^\n])*'
+token	CtString	"([^"\\\n]|\\[^\n])*"
+
+# these match less than the rules above, unless a closing quote or some digits
+# are missing
+error	UnterminatedChar	'([^'\\\r\n]|\\[^\r\n])*\\?
+error	UnterminatedString	"([^"\\\r\n]|\\[^\r\n])*\\?
+# a comment left open runs to the end of the file, but as it cannot hold a
+# `*/` it is always shorter than a closed one
+error	UnterminatedComment	/\*([^*]|\*+[^*/])*\**
+error	HexNoDigits	0x
+error	OctalDigit	0[0-7]*[89][0-9]*
+error	NoFraction	[0-9]+\.([eE][+-]?[0-9]+)?
+error	NoExponent	[0-9]+(\.[0-9]+)?[eE][+-]?
+
+token	Add		\+
+token	Sub		-
+token	Mul		\*
+token	Div		/
+token	Dot		\.
+token	And		&&
+token	Or		\|\|
+error	SingleAnd	&
+error	SingleOr	\|
+token	Not		!
+token	NotEq		!=
+token	Equal		==
+token	Assign		=
+token	Less		<
+token	LessEq		<=
+token	Greater		>
+token	GreaterEq	>=
+token	Comma		,
+token	Semicolon	;
+token	Question	\?
+token	Colon		:
+token	Lpar		\(
+token	Rpar		\)
+token	Lbracket	\[
+token	Rbracket	\]
+token	Lacc		{
+token	Racc		}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden token streams in tests/golden")
+
+func dumpTokens(tokens []Token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		var value interface{}
+		switch t.tokenType {
+		case Id, TypeName:
+			value = t.Ident()
+		case CtInt:
+			value = t.IntValue()
+		case CtReal:
+			value = t.RealValue()
+		case CtChar:
+			value = t.CharValue()
+		case CtString:
+			value = t.StringValue()
+		default:
+			fmt.Fprintf(&b, "%d %s\n", t.line, constLookup[t.tokenType])
+			continue
+		}
+		fmt.Fprintf(&b, "%d %s %q\n", t.line, constLookup[t.tokenType], fmt.Sprint(value))
+	}
+	return b.String()
+}
+
+// the golden files were recorded with the hand-written lexer that the
+// table-driven one replaced, so this checks they produce the same tokens; only
+// test_c.c changed since, as its single `&` is now recovered as an And
+func TestLexerGolden(t *testing.T) {
+	files, err := filepath.Glob("tests/*.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := string(content)
+		got := dumpTokens(getTokens(newLexer(text)))
+
+		golden := filepath.Join("tests", "golden", filepath.Base(file)+".tokens")
+		if *update {
+			if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != string(want) {
+			t.Errorf("%s: the tokens differ from %s:\n%s", file, golden, firstDiff(got, string(want)))
+		}
+	}
+}
+
+func firstDiff(got, want string) string {
+	g := strings.Split(got, "\n")
+	w := strings.Split(want, "\n")
+	for i := 0; i < len(g) && i < len(w); i++ {
+		if g[i] != w[i] {
+			return fmt.Sprintf("line %d: got %s, want %s", i+1, g[i], w[i])
+		}
+	}
+	return fmt.Sprintf("got %d lines, want %d", len(g), len(w))
+}
+
+func TestLexerDiagnostics(t *testing.T) {
+	tests := []struct {
+		text        string
+		tokens      []TokenType
+		diagnostics []string
+	}{
+		{
+			text:        "a & b",
+			tokens:      []TokenType{Id, And, Id, End},
+			diagnostics: []string{"error in line 1, column 3: expected `&&`, found single `&`"},
+		},
+		{
+			text:        "x = \"abc;\ny",
+			tokens:      []TokenType{Id, Assign, CtString, Id, End},
+			diagnostics: []string{"error in line 1, column 10: unterminated string literal starting at 1:5"},
+		},
+		{
+			text:        "'\\q' \"a\\zb\"",
+			tokens:      []TokenType{CtChar, CtString, End},
+			diagnostics: []string{"error in line 1, column 2: invalid escape sequence '\\q'", "error in line 1, column 8: invalid escape sequence '\\z'"},
+		},
+		{
+			text:        "'' 'ab'",
+			tokens:      []TokenType{CtChar, CtChar, End},
+			diagnostics: []string{"error in line 1, column 1: empty character literal", "error in line 1, column 4: character literal 'ab' has more than one character"},
+		},
+		{
+			text:        "0x+1. 2e- 09",
+			tokens:      []TokenType{CtInt, Add, CtReal, CtReal, CtInt, End},
+			diagnostics: []string{"error in line 1, column 1: hex literal has no digits", "error in line 1, column 4: real literal 1. has no digits after `.`", "error in line 1, column 7: real literal 2e- has no digits in the exponent", "error in line 1, column 11: invalid digit in octal literal 09"},
+		},
+		{
+			text:        "2147483647 2147483648 0x80000000 0777",
+			tokens:      []TokenType{CtInt, CtInt, CtInt, CtInt, End},
+			diagnostics: []string{"error in line 1, column 12: integer literal 2147483648 does not fit in a 32 bit int", "error in line 1, column 23: integer literal 0x80000000 does not fit in a 32 bit int"},
+		},
+		{
+			text:        "1e308 1e309 1e-400",
+			tokens:      []TokenType{CtReal, CtReal, CtReal, End},
+			diagnostics: []string{"error in line 1, column 7: real literal 1e309 does not fit in a double"},
+		},
+		{
+			text:        "\"ăș\\q\" 'ț' ș",
+			tokens:      []TokenType{CtString, CtChar, End},
+			diagnostics: []string{"error in line 1, column 4: invalid escape sequence '\\q'", "error in line 1, column 12: invalid character 'ș'"},
+		},
+		{
+			text:        "'\\x' '\\u12' '\\ud800' \"\xff\"",
+			tokens:      []TokenType{CtChar, CtChar, CtChar, CtString, End},
+			diagnostics: []string{"error in line 1, column 2: escape sequence '\\x' has no hex digits", "error in line 1, column 7: escape sequence '\\u' needs 4 hex digits", "error in line 1, column 14: escape sequence '\\ud800' is not a valid character", "error in line 1, column 23: invalid UTF-8 byte 0xff"},
+		},
+		{
+			text:        "int x; /* the end\n* of x *",
+			tokens:      []TokenType{Int, Id, Semicolon, End},
+			diagnostics: []string{"error in line 2, column 9: unterminated comment started at line 1"},
+		},
+		{
+			text:        "a\x00b\x01 \u0085\n\"\x1b\"",
+			tokens:      []TokenType{Id, Id, CtString, End},
+			diagnostics: []string{"error in line 1, column 2: NUL character in the source", "error in line 1, column 4: invalid control character U+0001", "error in line 1, column 6: invalid control character U+0085", "error in line 2, column 2: control character U+001B in literal, write it as an escape sequence"},
+		},
+		{
+			text:        "// c\x00d\nx /* a\x01\n\x00 */ y",
+			tokens:      []TokenType{Id, Id, End},
+			diagnostics: []string{"error in line 1, column 5: NUL character in a comment", "error in line 2, column 7: invalid control character U+0001 in a comment", "error in line 3, column 1: NUL character in a comment"},
+		},
+		{
+			text:        "int\n  @x",
+			tokens:      []TokenType{Int, Id, End},
+			diagnostics: []string{"error in line 2, column 3: invalid character '@'"},
+		},
+	}
+	for _, test := range tests {
+		lx := newLexer(test.text)
+		var got []TokenType
+		for _, token := range getTokens(lx) {
+			got = append(got, token.tokenType)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.tokens) {
+			t.Errorf("%q: got the tokens %v, want %v", test.text, got, test.tokens)
+		}
+		var diagnostics []string
+		for _, d := range lx.diagnostics {
+			diagnostics = append(diagnostics, d.String())
+		}
+		if strings.Join(diagnostics, "\n") != strings.Join(test.diagnostics, "\n") {
+			t.Errorf("%q: got the diagnostics\n%s\nwant\n%s", test.text, strings.Join(diagnostics, "\n"), strings.Join(test.diagnostics, "\n"))
+		}
+	}
+}
+
+func TestTokenLexeme(t *testing.T) {
+	tokens := getTokens(newLexer("0x1F 017 1.50 1. 'a' \"b\\n\" x"))
+	want := []string{"0x1F", "017", "1.50", "1.", "'a'", "\"b\\n\"", "x", ""}
+	for i, token := range tokens {
+		if token.Lexeme() != want[i] {
+			t.Errorf("token %d: got the lexeme %q, want %q", i, token.Lexeme(), want[i])
+		}
+	}
+	if tokens[0].IntValue() != 31 || tokens[1].IntValue() != 15 || tokens[3].RealValue() != 1 || tokens[4].CharValue() != 'a' || tokens[5].StringValue() != "b\n" || tokens[6].Ident() != "x" {
+		t.Errorf("wrong token values: %+v", tokens)
+	}
+}
+
+func TestLexerUTF8(t *testing.T) {
+	tokens := getTokens(newLexer("'ă' '\\u0103' '\\xff' \"Ți\\x41\\u0219\""))
+	if tokens[0].CharValue() != 'ă' || tokens[1].CharValue() != 'ă' || tokens[2].CharValue() != 0xff {
+		t.Errorf("got the chars %d %d %d", tokens[0].CharValue(), tokens[1].CharValue(), tokens[2].CharValue())
+	}
+	if tokens[3].StringValue() != "ȚiAș" {
+		t.Errorf("got the string %q, want %q", tokens[3].StringValue(), "ȚiAș")
+	}
+	if tokens[3].col != 21 {
+		t.Errorf("the string starts at column %d, want 21", tokens[3].col)
+	}
+}
+
+func TestLexerTrivia(t *testing.T) {
+	lx := newLexer("// header\n\nint x; // the x\n/* two\n lines */ int y;\n\n")
+	lx.keepTrivia = true
+	tokens := getTokens(lx)
+	dump := func(trivia []Trivia) string {
+		var parts []string
+		for _, tr := range trivia {
+			parts = append(parts, fmt.Sprintf("%d:%d %s %q", tr.line, tr.col, triviaLookup[tr.kind], tr.text))
+		}
+		return strings.Join(parts, ", ")
+	}
+	tests := []struct {
+		id                int
+		leading, trailing string
+	}{
+		{0, `1:1 Comment "// header", 2:1 BlankLine ""`, ""},
+		{1, "", ""},
+		{2, "", `3:8 Comment "// the x"`},
+		{3, `4:1 Comment "/* two\n lines */"`, ""},
+		{6, `6:1 BlankLine ""`, ""},
+	}
+	for _, test := range tests {
+		if got := dump(tokens[test.id].leading); got != test.leading {
+			t.Errorf("token %d: got the leading trivia %s, want %s", test.id, got, test.leading)
+		}
+		if got := dump(tokens[test.id].trailing); got != test.trailing {
+			t.Errorf("token %d: got the trailing trivia %s, want %s", test.id, got, test.trailing)
+		}
+	}
+}
+
+// an unterminated comment ends the file, and is trivia of the End token
+func TestLexerTriviaUnterminatedComment(t *testing.T) {
+	lx := newLexer("int x;\n\n// hello\n/* open")
+	lx.keepTrivia = true
+	tokens := getTokens(lx)
+	end := tokens[len(tokens)-1]
+	var got []string
+	for _, tr := range end.leading {
+		got = append(got, fmt.Sprintf("%d:%d %s %q", tr.line, tr.col, triviaLookup[tr.kind], tr.text))
+	}
+	want := `2:1 BlankLine "", 3:1 Comment "// hello", 4:1 Comment "/* open"`
+	if end.tokenType != End || strings.Join(got, ", ") != want {
+		t.Errorf("got the %s token with the leading trivia %s, want End with %s", constLookup[end.tokenType], strings.Join(got, ", "), want)
+	}
+	if len(lx.diagnostics) != 1 || lx.diagnostics[0].String() != "error in line 4, column 8: unterminated comment started at line 4" {
+		t.Errorf("got the diagnostics %v", lx.diagnostics)
+	}
+}
+
+// keeping the trivia must not change the tokens the parser gets
+func TestLexerTriviaTokens(t *testing.T) {
+	files, err := filepath.Glob("tests/*.c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lx := newLexer(string(content))
+		lx.keepTrivia = true
+		if got, want := dumpTokens(getTokens(lx)), dumpTokens(getTokens(newLexer(string(content)))); got != want {
+			t.Errorf("%s: the tokens differ with trivia:\n%s", file, firstDiff(got, want))
+		}
+	}
+}
+
+func TestWriteTokens(t *testing.T) {
+	tokens := getTokens(newLexer("x = 0x1F;\ns = \"a,\\\"b\";"))
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", `{"file":"f.c","line":1,"col":1,"type":"Id","lexeme":"x","value":"x"}
+{"file":"f.c","line":1,"col":3,"type":"Assign","lexeme":"=","value":null}
+{"file":"f.c","line":1,"col":5,"type":"CtInt","lexeme":"0x1F","value":31}
+`},
+		{"csv", `file,line,col,type,lexeme,value
+f.c,1,1,Id,x,x
+f.c,1,3,Assign,=,
+f.c,1,5,CtInt,0x1F,31
+`},
+		{"compact", `f.c:1:1 Id "x"
+f.c:1:3 Assign "="
+f.c:1:5 CtInt "0x1F"
+`},
+	}
+	for _, test := range tests {
+		var b strings.Builder
+		if err := writeTokens(&b, "f.c", tokens, test.format); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(b.String(), test.want) {
+			t.Errorf("%s: got\n%s\nwant it to start with\n%s", test.format, b.String(), test.want)
+		}
+	}
+
+	var b strings.Builder
+	if err := writeTokens(&b, "f.c", tokens[6:7], "json"); err != nil || b.String() != `{"file":"f.c","line":2,"col":5,"type":"CtString","lexeme":"\"a,\\\"b\"","value":"a,\"b"}`+"\n" {
+		t.Errorf("got the string record %s, %v", b.String(), err)
+	}
+	if err := writeTokens(&b, "f.c", tokens, "xml"); err == nil {
+		t.Errorf("the unknown format xml was accepted")
+	}
+}
+
+// the columns are counted as the lexer moves, so a long line takes no longer
+// than the same tokens on many lines
+func BenchmarkLexerLongLine(b *testing.B) {
+	for _, sep := range []string{" ", "\n"} {
+		text := strings.Repeat("x = 'ă' + 1;"+sep, 10000)
+		b.Run(fmt.Sprintf("%q", sep), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				getTokens(newLexer(text))
+			}
+		})
+	}
+}
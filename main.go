@@ -1,12 +1,20 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type TokenType int
@@ -17,6 +25,7 @@ const (
 	CtChar
 	CtString
 	Id
+	TypeName
 	End
 	Div
 	Add
@@ -35,6 +44,8 @@ const (
 	GreaterEq
 	Comma
 	Semicolon
+	Question
+	Colon
 	Lpar
 	Rpar
 	Lbracket
@@ -46,11 +57,13 @@ const (
 	Char
 	Double
 	Else
+	Enum
 	For
 	If
 	Int
 	Return
 	Struct
+	Typedef
 	Void
 	While
 )
@@ -61,6 +74,7 @@ var constLookup = map[TokenType]string{
 	CtChar:    "CtChar",
 	CtString:  "CtString",
 	Id:        "Id",
+	TypeName:  "TypeName",
 	End:       "End",
 	Div:       "Div",
 	Add:       "Add",
@@ -79,6 +93,8 @@ var constLookup = map[TokenType]string{
 	GreaterEq: "GreaterEq",
 	Comma:     "Comma",
 	Semicolon: "Semicolon",
+	Question:  "Question",
+	Colon:     "Colon",
 	Lpar:      "Lpar",
 	Rpar:      "Rpar",
 	Lbracket:  "Lbracket",
@@ -90,774 +106,1123 @@ var constLookup = map[TokenType]string{
 	Char:      "Char",
 	Double:    "Double",
 	Else:      "Else",
+	Enum:      "Enum",
 	For:       "For",
 	If:        "If",
 	Int:       "Int",
 	Return:    "Return",
 	Struct:    "Struct",
+	Typedef:   "Typedef",
 	Void:      "Void",
 	While:     "While",
 }
 
 type Token struct {
 	tokenType TokenType
-	value     interface{}
+	lexeme    string  // the text of the token, as it was written
+	ctInt     int64   // the value of CtInt, and the code point of CtChar
+	ctReal    float64 // the value of CtReal
+	text      string  // the name of Id and TypeName, the value of CtString
 	line      uint
+	col       uint
+	leading   []Trivia // only with Lexer.keepTrivia
+	trailing  []Trivia
+}
+
+// the value accessors are only meaningful for the token types they name
+
+func (t Token) IntValue() int64 {
+	return t.ctInt
+}
+
+func (t Token) RealValue() float64 {
+	return t.ctReal
+}
+
+// the code point of a CtChar
+func (t Token) CharValue() rune {
+	return rune(t.ctInt)
+}
+
+func (t Token) StringValue() string {
+	return t.text
+}
+
+// the name of an Id or TypeName
+func (t Token) Ident() string {
+	return t.text
+}
+
+func (t Token) Lexeme() string {
+	return t.lexeme
+}
+
+// whether the token carries a value besides its type
+func (t Token) hasValue() bool {
+	switch t.tokenType {
+	case Id, TypeName, CtInt, CtReal, CtChar, CtString:
+		return true
+	}
+	return false
 }
 
 // ---------------------- ANLEX --------------------------------------
-func getNextToken(text *string, curPos *uint, currLine *uint) Token {
 
-	var tokenStr string = ""
-	var state uint = 0
-	var tokenChar byte
+// the tokens are described by lexer.spec, which lexgen compiles into the
+// tables of a DFA in lexer_table.go
+//go:generate go run ./cmd/lexgen -o lexer_table.go lexer.spec
+
+type lexRuleKind int
+
+const (
+	lexToken lexRuleKind = iota
+	lexSkip
+	lexError
+)
+
+type lexRule struct {
+	kind      lexRuleKind
+	tokenType TokenType
+	name      string
+}
+
+type Diagnostic struct {
+	line uint
+	col  uint
+	msg  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("error in line %d, column %d: %s", d.line, d.col, d.msg)
+}
+
+type Lexer struct {
+	text string
+	pos  uint
+	line uint
+	// the column of pos, from 1; columns count characters, not the bytes of
+	// their UTF-8 encoding
+	column uint
+	// the lexer reports its errors here and carries on, so one run finds
+	// all of them
+	diagnostics []Diagnostic
+	// keepTrivia attaches the comments and blank lines to the tokens, for
+	// tools which print the source back; the parser does not need them
+	keepTrivia bool
+	trivia     []Trivia // the leading trivia of the next token
+}
+
+type TriviaKind int
+
+const (
+	TriviaComment TriviaKind = iota
+	TriviaBlankLine
+)
+
+var triviaLookup = map[TriviaKind]string{
+	TriviaComment:   "Comment",
+	TriviaBlankLine: "BlankLine",
+}
+
+// a comment or a blank line; the trivia before a token, from the end of the
+// line of the previous token, is leading, and the comments after it on the
+// same line are trailing
+type Trivia struct {
+	kind TriviaKind
+	text string // the comment, with its delimiters
+	line uint
+	col  uint
+}
+
+func newLexer(text string) *Lexer {
+	return &Lexer{
+		text:   text,
+		line:   1,
+		column: 1,
+	}
+}
+
+func (lx *Lexer) errorf(line uint, col uint, format string, args ...interface{}) {
+	lx.diagnostics = append(lx.diagnostics, Diagnostic{line: line, col: col, msg: fmt.Sprintf(format, args...)})
+}
+
+// runs the DFA from start as long as a rule can still match; it returns the
+// rule of the longest match and where it ends, or -1 if no rule matches
+func (lx *Lexer) match(start uint) (int16, uint) {
+	var state int16 = 0
+	var rule int16 = -1
+	end := start
+	for pos := start; int(pos) < len(lx.text); pos++ {
+		state = lexTrans[int(state)*lexNumClasses+int(lexClass[lx.text[pos]])]
+		if state < 0 {
+			break
+		}
+		if lexAccept[state] >= 0 {
+			rule = lexAccept[state]
+			end = pos + 1
+		}
+	}
+	return rule, end
+}
+
+// moves past the matched text, counting its lines
+func (lx *Lexer) advance(end uint) string {
+	start := lx.pos
+	lexeme := lx.text[start:end]
+	lx.pos = end
+	// only the text after the last newline moves the column
+	rest := lexeme
+	if i := strings.LastIndexByte(lexeme, '\n'); i >= 0 {
+		lx.line += uint(strings.Count(lexeme, "\n"))
+		lx.column = 1
+		rest = lexeme[i+1:]
+	}
+	lx.column += uint(utf8.RuneCountInString(rest))
+	return lexeme
+}
+
+func (lx *Lexer) getNextToken() Token {
 
 	for {
-		if int(*curPos) == len(*text) {
-			return Token{
+		if int(lx.pos) == len(lx.text) {
+			token := Token{
 				tokenType: End,
-				line:      *currLine,
+				line:      lx.line,
+				col:       lx.column,
+				leading:   lx.trivia,
 			}
+			lx.trivia = nil
+			return token
 		}
-		var c byte = (*text)[*curPos]
-		*curPos += 1
-
-		switch state {
-		case 0:
-			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c == '_') {
-				state = 30
-				tokenStr += string(c)
-			} else if c == '\x00' {
-				*curPos -= 1
-				return Token{
-					tokenType: End,
-					line:      *currLine,
-				}
-			} else if c == '+' {
-				return Token{
-					tokenType: Add,
-					line:      *currLine,
-				}
-			} else if c == '-' {
-				return Token{
-					tokenType: Sub,
-					line:      *currLine,
-				}
-			} else if c == '*' {
-				return Token{
-					tokenType: Mul,
-					line:      *currLine,
-				}
-			} else if c == '.' {
-				return Token{
-					tokenType: Dot,
-					line:      *currLine,
-				}
-			} else if c == ',' {
-				return Token{
-					tokenType: Comma,
-					line:      *currLine,
-				}
-			} else if c == ';' {
-				return Token{
-					tokenType: Semicolon,
-					line:      *currLine,
-				}
-			} else if c == '(' {
-				return Token{
-					tokenType: Lpar,
-					line:      *currLine,
-				}
-			} else if c == ')' {
-				return Token{
-					tokenType: Rpar,
-					line:      *currLine,
-				}
-			} else if c == '[' {
-				return Token{
-					tokenType: Lbracket,
-					line:      *currLine,
-				}
-			} else if c == ']' {
-				return Token{
-					tokenType: Rbracket,
-					line:      *currLine,
-				}
-			} else if c == '{' {
-				return Token{
-					tokenType: Lacc,
-					line:      *currLine,
-				}
-			} else if c == '}' {
-				return Token{
-					tokenType: Racc,
-					line:      *currLine,
-				}
-			} else if c == '&' {
-				state = 15
-			} else if c == '|' {
-				state = 16
-			} else if c == '!' {
-				state = 17
-			} else if c == '=' {
-				state = 18
-			} else if c == '<' {
-				state = 19
-			} else if c == '>' {
-				state = 20
-			} else if c == ' ' || c == '\r' || c == '\n' || c == '\t' {
-				state = 0
-				if c == '\n' {
-					*currLine += 1
-				}
-			} else if c == '/' {
-				state = 12
-			} else if c == '0' {
-				state = 2
-				tokenStr += string(c)
-			} else if c >= '1' && c <= '9' {
-				state = 1
-				tokenStr += string(c)
-			} else if c == '\'' {
-				state = 21
-			} else if c == '"' {
-				state = 25
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 1:
-			if c >= '0' && c <= '9' {
-				tokenStr += string(c)
-			} else if c == '.' {
-				state = 7
-				tokenStr += string(c)
-			} else if c == 'e' || c == 'E' {
-				state = 9
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				int_nr, err := strconv.Atoi(tokenStr)
-				if err != nil {
-					int_nr = 0
-				}
-				return Token{
-					tokenType: CtInt,
-					value:     int_nr,
-					line:      *currLine,
-				}
-			}
-			break
 
-		case 2:
-			// hex
-			if c == 'x' {
-				state = 4
-				tokenStr += string(c)
-			} else if c >= '0' && c <= '7' {
-				state = 3
-				tokenStr += string(c)
-			} else if c == '8' || c == '9' {
-				state = 6
-				tokenStr += string(c)
-			} else if c == 'e' || c == 'E' {
-				state = 9
-				tokenStr += string(c)
-			} else if c == '.' {
-				state = 7
-				tokenStr += string(c)
-			} else {
-				*curPos-=1
-				return Token{
-					tokenType: CtInt,
-					value:     0,
-					line:      *currLine,
-				}
+		start := lx.pos
+		line, col := lx.line, lx.column
+		rule, end := lx.match(start)
+		if rule < 0 {
+			r, size := utf8.DecodeRuneInString(lx.text[start:])
+			switch {
+			case r == utf8.RuneError && size == 1:
+				lx.errorf(line, col, "invalid UTF-8 byte 0x%02x", lx.text[start])
+			case r == 0:
+				// the rest of the file is still lexed, so that a stray NUL
+				// does not hide it
+				lx.errorf(line, col, "NUL character in the source")
+			case unicode.IsControl(r):
+				lx.errorf(line, col, "invalid control character U+%04X", r)
+			default:
+				lx.errorf(line, col, "invalid character %q", r)
 			}
+			lx.pos = start + uint(size)
+			lx.column++
+			continue
+		}
 
-			break
-		case 3:
-			// Octal
-			if c >= '0' && c <= '7' {
-				tokenStr += string(c)
-			} else if c == '8' || c == '9' {
-				state = 6
-				tokenStr += string(c)
-			} else if c == 'e' || c == 'E' {
-				state = 9
-				tokenStr += string(c)
-			} else if c == '.' {
-				state = 7
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				int_nr, err := strconv.ParseInt(tokenStr, 0, 64)
-				if err != nil {
-					int_nr = 0
-				}
-				return Token{
-					tokenType: CtInt,
-					value:     int_nr,
-					line:      *currLine,
-				}
-			}
-			break
-		case 4:
-			if (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') || (c >= '0' && c <= '9') {
-				state = 5
-				tokenStr += string(c)
-			} else {
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
+		lexeme := lx.advance(end)
+		var token Token
+		switch lexRules[rule].kind {
+		case lexSkip:
+			if lexRules[rule].name != "Space" {
+				lx.checkComment(lexeme, line, col)
 			}
-			break
-		case 5:
-			// hex
-			if (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') || (c >= '0' && c <= '9') {
-				state = 5
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				int_nr, err := strconv.ParseInt(tokenStr, 0, 64)
-				if err != nil {
-					int_nr = 0
-				}
-				return Token{
-					tokenType: CtInt,
-					value:     int_nr,
-					line:      *currLine,
-				}
+			if lx.keepTrivia {
+				lx.addTrivia(&lx.trivia, lexRules[rule].name, lexeme, line, col)
 			}
-			break
-		case 6:
-			if c >= '0' && c <= '9' {
-				tokenStr += string(c)
-			} else if c == '.' {
-				state = 7
-				tokenStr += string(c)
-			} else if c == 'e' || c == 'E' {
-				state = 9
-				tokenStr += string(c)
-			} else {
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
+			continue
+		case lexError:
+			token = lx.recoverToken(lexRules[rule].name, lexeme, line, col)
+		default:
+			token = lx.makeToken(lexRules[rule].tokenType, lexeme, line, col)
+		}
+		if lx.keepTrivia {
+			token.leading = lx.trivia
+			lx.trivia = nil
+			lx.trailingTrivia(&token)
+		}
+		return token
+	}
+}
 
-		case 7:
-			if c >= '0' && c <= '9' {
-				state = 8
-				tokenStr += string(c)
-			} else {
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 8:
-			if c >= '0' && c <= '9' {
-				state = 8
-				tokenStr += string(c)
-			} else if c == 'e' || c == 'E' {
-				state = 9
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				float_nr, err := strconv.ParseFloat(tokenStr, 64)
-				if err != nil {
-					float_nr = 0.0
-				}
-				return Token{
-					tokenType: CtReal,
-					value:     float_nr,
-					line:      *currLine,
-				}
-			}
-			break
-		case 9:
-			if c == '+' || c == '-' {
-				state = 10
-				tokenStr += string(c)
-			} else if c >= '0' && c <= '9' {
-				state = 11
-				tokenStr += string(c)
-			} else {
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
+// records the text of a skip rule as trivia; a run of spaces which holds n
+// newlines after the end of a line is n-1 blank lines
+func (lx *Lexer) addTrivia(trivia *[]Trivia, name string, lexeme string, line uint, col uint) {
+	if name != "Space" {
+		*trivia = append(*trivia, Trivia{kind: TriviaComment, text: lexeme, line: line, col: col})
+		return
+	}
+	n := strings.Count(lexeme, "\n")
+	if col > 1 {
+		n--
+		line++
+	}
+	for i := 0; i < n; i++ {
+		*trivia = append(*trivia, Trivia{kind: TriviaBlankLine, line: line + uint(i), col: 1})
+	}
+}
 
-			break
-		case 10:
-			if c >= '0' && c <= '9' {
-				state = 11
-				tokenStr += string(c)
-			} else {
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 11:
-			if c >= '0' && c <= '9' {
-				state = 11
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				float_nr, err := strconv.ParseFloat(tokenStr, 64)
-				if err != nil {
-					float_nr = 0.0
-				}
-				return Token{
-					tokenType: CtReal,
-					value:     float_nr,
-					line:      *currLine,
-				}
-			}
-			break
-		case 12:
-			if c == '*' {
-				state = 13
-			} else if c == '/' {
-				state = 29
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Div,
-					line:      *currLine,
-				}
-			}
-			break
-		case 13:
-			if c == '*' {
-				state = 14
-			} else if c == '\n' {
-				*currLine += 1
-			} else {
-			}
-			break
-		case 14:
-			if c == '*' {
-				state = 14
-			} else if c == '/' {
-				state = 0
-			} else if c == '\n' {
-				state = 13
-				*currLine += 1
-			} else {
-				state = 13
-			}
-			break
-		case 15:
-			if c == '&' {
-				return Token{
-					tokenType: And,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 16:
-			if c == '|' {
-				return Token{
-					tokenType: Or,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 17:
-			if c == '=' {
-				return Token{
-					tokenType: NotEq,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Not,
-					line:      *currLine,
-				}
-			}
-			break
-		case 18:
-			if c == '=' {
-				return Token{
-					tokenType: Equal,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Assign,
-					line:      *currLine,
-				}
-			}
-			break
-		case 19:
-			if c == '=' {
-				return Token{
-					tokenType: LessEq,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Less,
-					line:      *currLine,
-				}
-			}
-			break
-		case 20:
-			if c == '=' {
-				return Token{
-					tokenType: GreaterEq,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Greater,
-					line:      *currLine,
-				}
-			}
-			break
-		case 21:
-			if c == '\\' {
-				state = 22
-			} else {
-				state = 24
-				tokenChar = c
-			}
-			break
-		case 22:
-			if c == 'a' {
-				state = 23
-				tokenChar = '\x07'
-			} else if c == 'b' {
-				state = 23
-				tokenChar = '\x08'
-			} else if c == 't' {
-				state = 23
-				tokenChar = '\x09'
-			} else if c == 'n' {
-				state = 23
-				tokenChar = '\x0A'
-			} else if c == 'v' {
-				state = 23
-				tokenChar = '\x0B'
-			} else if c == 'f' {
-				state = 23
-				tokenChar = '\x0C'
-			} else if c == 'r' {
-				state = 23
-				tokenChar = '\x0D'
-			} else if c == '0' {
-				state = 23
-				tokenChar = '\x00'
-			} else if c == '?' || c == '"' || c == '\'' || c == '\\' {
-				state = 23
-				tokenChar = c
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			}
-			break
-		case 23:
-			if c == '\'' {
-				return Token{
-					tokenType: CtChar,
-					value:     tokenChar,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
+// reports the NUL and control characters in the text of a comment starting at
+// line:col, which the comment rules let through so that the comment does not
+// end at them
+func (lx *Lexer) checkComment(text string, line uint, col uint) {
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			line++
+			col = 1
+			continue
+		case r == 0:
+			lx.errorf(line, col, "NUL character in a comment")
+		case unicode.IsControl(r) && r != '\t' && r != '\r':
+			lx.errorf(line, col, "invalid control character U+%04X in a comment", r)
+		}
+		col++
+	}
+}
+
+// moves the comments which follow the token on its line into its trailing
+// trivia; the end of the line is left for the leading trivia of the next one
+func (lx *Lexer) trailingTrivia(token *Token) {
+	for int(lx.pos) < len(lx.text) {
+		line, col := lx.line, lx.column
+		rule, end := lx.match(lx.pos)
+		if rule < 0 || lexRules[rule].kind != lexSkip {
+			return
+		}
+		name := lexRules[rule].name
+		if name == "Space" && strings.IndexByte(lx.text[lx.pos:end], '\n') >= 0 {
+			return
+		}
+		lexeme := lx.advance(end)
+		if name != "Space" {
+			lx.checkComment(lexeme, line, col)
+			token.trailing = append(token.trailing, Trivia{kind: TriviaComment, text: lexeme, line: line, col: col})
+		}
+	}
+}
+
+// builds the token matched by a rule, decoding the value of the constants
+func (lx *Lexer) makeToken(tokenType TokenType, lexeme string, line uint, col uint) Token {
+	token := Token{
+		tokenType: tokenType,
+		lexeme:    lexeme,
+		line:      line,
+		col:       col,
+	}
+	switch tokenType {
+	case Id:
+		token.text = lexeme
+	case CtInt:
+		// the base is given by the prefix, 0 for octal and 0x for hex; on
+		// overflow ParseInt returns the largest int, which is used from then on
+		int_nr, err := strconv.ParseInt(lexeme, 0, int(intBits))
+		if err != nil {
+			lx.errorf(line, col, "integer literal %s does not fit in a %d bit int", lexeme, intBits)
+		}
+		token.ctInt = int_nr
+	case CtReal:
+		// on overflow ParseFloat returns an infinity, which is used from then
+		// on; the malformed literals were already reported by their error rules
+		float_nr, err := strconv.ParseFloat(lexeme, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			lx.errorf(line, col, "real literal %s does not fit in a double", lexeme)
+		} else if err != nil {
+			float_nr = 0.0
+		}
+		token.ctReal = float_nr
+	case CtChar:
+		str := lx.unescape(lexeme[1:len(lexeme)-1], line, col+1)
+		if len(str) == 0 {
+			lx.errorf(line, col, "empty character literal")
+		} else {
+			r, size := firstChar(str)
+			if size < len(str) {
+				lx.errorf(line, col, "character literal %s has more than one character", lexeme)
 			}
+			token.ctInt = int64(r)
+		}
+	case CtString:
+		token.text = lx.unescape(lexeme[1:len(lexeme)-1], line, col+1)
+	}
+	return token
+}
+
+// reports the error matched by an error rule of lexer.spec and returns the
+// token that was most likely meant, so that the parser can go on
+func (lx *Lexer) recoverToken(name string, lexeme string, line uint, col uint) Token {
+	switch name {
+	case "UnterminatedChar":
+		str := lx.unescape(lexeme[1:], line, col+1)
+		lx.errorf(lx.line, lx.column, "unterminated character literal starting at %d:%d", line, col)
+		token := Token{
+			tokenType: CtChar,
+			lexeme:    lexeme,
+			line:      line,
+			col:       col,
+		}
+		if len(str) > 0 {
+			r, _ := firstChar(str)
+			token.ctInt = int64(r)
+		}
+		return token
+	case "UnterminatedString":
+		str := lx.unescape(lexeme[1:], line, col+1)
+		lx.errorf(lx.line, lx.column, "unterminated string literal starting at %d:%d", line, col)
+		return Token{
+			tokenType: CtString,
+			lexeme:    lexeme,
+			text:      str,
+			line:      line,
+			col:       col,
+		}
+	case "UnterminatedComment":
+		// the comment runs to the end of the file, so the End token follows;
+		// getNextToken gives it the comment as leading trivia
+		lx.checkComment(lexeme, line, col)
+		lx.errorf(lx.line, lx.column, "unterminated comment started at line %d", line)
+		if lx.keepTrivia {
+			lx.addTrivia(&lx.trivia, "BlockComment", lexeme, line, col)
+		}
+		return Token{
+			tokenType: End,
+			line:      lx.line,
+			col:       lx.column,
+		}
+	case "HexNoDigits":
+		lx.errorf(line, col, "hex literal has no digits")
+		return lx.recovered(CtInt, "0", lexeme, line, col)
+	case "OctalDigit":
+		lx.errorf(line, col, "invalid digit in octal literal %s", lexeme)
+		return lx.recovered(CtInt, "0", lexeme, line, col)
+	case "NoFraction":
+		lx.errorf(line, col, "real literal %s has no digits after `.`", lexeme)
+		return lx.recovered(CtReal, strings.Replace(lexeme, ".", ".0", 1), lexeme, line, col)
+	case "NoExponent":
+		lx.errorf(line, col, "real literal %s has no digits in the exponent", lexeme)
+		return lx.recovered(CtReal, strings.TrimRight(lexeme, "eE+-"), lexeme, line, col)
+	case "SingleAnd":
+		lx.errorf(line, col, "expected `&&`, found single `&`")
+		return lx.makeToken(And, lexeme, line, col)
+	case "SingleOr":
+		lx.errorf(line, col, "expected `||`, found single `|`")
+		return lx.makeToken(Or, lexeme, line, col)
+	}
+	panic("lexer.spec has no recovery for the error rule " + name)
+}
+
+// builds the token for the corrected text meant, but keeps the lexeme that was
+// actually written
+func (lx *Lexer) recovered(tokenType TokenType, meant string, lexeme string, line uint, col uint) Token {
+	token := lx.makeToken(tokenType, meant, line, col)
+	token.lexeme = lexeme
+	return token
+}
+
+// the value of a char literal is the code point of its first UTF-8 character;
+// a byte which does not start one, as given by `\xff`, stands for itself
+func firstChar(str string) (rune, int) {
+	r, size := utf8.DecodeRuneInString(str)
+	if r == utf8.RuneError && size == 1 {
+		return rune(str[0]), 1
+	}
+	return r, size
+}
+
+// the number of hex digits at the start of s, at most max
+func hexPrefix(s string, max int) int {
+	n := 0
+	for n < len(s) && n < max && strings.IndexByte("0123456789abcdefABCDEF", s[n]) >= 0 {
+		n++
+	}
+	return n
+}
+
+// replaces the escape sequences of a char or string literal whose text starts
+// at line:col; an invalid sequence is reported and stands for the escaped
+// character. The result is UTF-8, `\uXXXX` being encoded and `\xHH` giving
+// the byte HH
+func (lx *Lexer) unescape(s string, line uint, col uint) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			lx.errorf(line, col, "invalid UTF-8 byte 0x%02x", s[i])
+		} else if unicode.IsControl(r) && r != '\t' {
+			lx.errorf(line, col, "control character U+%04X in literal, write it as an escape sequence", r)
+		}
+		if r != '\\' {
+			b.WriteString(s[i : i+size])
+			i += size
+			col++
+			continue
+		}
+		// an unterminated literal may end with a lone `\`
+		if i+1 == len(s) {
 			break
-		case 24:
-			if c == '\'' {
-				return Token{
-					tokenType: CtChar,
-					value:     tokenChar,
-					line:      *currLine,
-				}
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
+		}
+		escCol := col
+		r, size = utf8.DecodeRuneInString(s[i+1:])
+		i += 1 + size
+		col += 2
+		switch r {
+		case 'a':
+			b.WriteByte('\x07')
+		case 'b':
+			b.WriteByte('\x08')
+		case 't':
+			b.WriteByte('\x09')
+		case 'n':
+			b.WriteByte('\x0A')
+		case 'v':
+			b.WriteByte('\x0B')
+		case 'f':
+			b.WriteByte('\x0C')
+		case 'r':
+			b.WriteByte('\x0D')
+		case '0':
+			b.WriteByte('\x00')
+		case '?', '"', '\'', '\\':
+			b.WriteRune(r)
+		case 'x':
+			n := hexPrefix(s[i:], 2)
+			if n == 0 {
+				lx.errorf(line, escCol, "escape sequence '\\x' has no hex digits")
+				b.WriteRune(r)
+				break
 			}
-			break
-		// CtString
-		case 25:
-			if c == '\\' {
-				state = 26
-			} else if c == '"' {
-				return Token{
-					tokenType: CtString,
-					value:     "",
-					line:      *currLine,
-				}
-			} else if c == '\n' {
-				*currLine += 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
+			v, _ := strconv.ParseUint(s[i:i+n], 16, 8)
+			b.WriteByte(byte(v))
+			i += n
+			col += uint(n)
+		case 'u':
+			n := hexPrefix(s[i:], 4)
+			v, _ := strconv.ParseUint(s[i:i+n], 16, 32)
+			if n < 4 {
+				lx.errorf(line, escCol, "escape sequence '\\u' needs 4 hex digits")
+				b.WriteRune(r)
+			} else if !utf8.ValidRune(rune(v)) {
+				lx.errorf(line, escCol, "escape sequence '\\u%s' is not a valid character", s[i:i+n])
+				b.WriteRune(r)
 			} else {
-				state = 28
-				tokenStr += string(c)
+				b.WriteRune(rune(v))
 			}
-			break
-		case 26:
-			if c == 'a' {
-				state = 27
-				tokenStr += string('\x07')
-			} else if c == 'b' {
-				state = 27
-				tokenStr += string('\x08')
-			} else if c == 't' {
-				state = 27
-				tokenStr += string('\x09')
-			} else if c == 'n' {
-				state = 27
-				tokenStr += string('\x0A')
-			} else if c == 'v' {
-				state = 27
-				tokenStr += string('\x0B')
-			} else if c == 'f' {
-				state = 27
-				tokenStr += string('\x0C')
-			} else if c == 'r' {
-				state = 27
-				tokenStr += string('\x0D')
-			} else if c == '0' {
-				state = 27
-				tokenStr += string('\x00')
-			} else if c == '?' || c == '"' || c == '\'' || c == '\\' {
-				state = 27
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
+			i += n
+			col += uint(n)
+		default:
+			lx.errorf(line, escCol, "invalid escape sequence '\\%c'", r)
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func getTokens(lx *Lexer) []Token {
+	var tokens []Token
+
+outer:
+	for {
+		t := lx.getNextToken()
+		tokens = append(tokens, t)
+		switch t.tokenType {
+		case End:
+			break outer
+		default:
+			continue
+		}
+	}
+
+	return tokens
+}
+
+// prints the trivia of the tokens too, if the lexer kept it
+func printTokens(w io.Writer, tokens []Token) {
+	fmt.Fprintf(w, "%-10s %-10s \t %-10s\n", "line", "token", "value")
+	fmt.Fprintf(w, "%s\n", strings.Repeat("-", 30))
+	for _, token := range tokens {
+		printTrivia(w, token.leading)
+		switch token.tokenType {
+		case Id, TypeName:
+			fmt.Fprintf(w, "%-10d %-10s\t %-10s\n", token.line, constLookup[token.tokenType], token.Ident())
+		case CtInt:
+			fmt.Fprintf(w, "%-10d %-10s\t %-10d\n", token.line, constLookup[token.tokenType], token.IntValue())
+		case CtReal:
+			fmt.Fprintf(w, "%-10d %-10s\t %-10f\n", token.line, constLookup[token.tokenType], token.RealValue())
+		case CtChar:
+			fmt.Fprintf(w, "%-10d %-10s\t %-10s\n", token.line, constLookup[token.tokenType], string(token.CharValue()))
+		case CtString:
+			fmt.Fprintf(w, "%-10d %-10s\t %-10s\n", token.line, constLookup[token.tokenType], token.StringValue())
+		default:
+			fmt.Fprintf(w, "%-10d %-10s\n", token.line, constLookup[token.tokenType])
+		}
+		printTrivia(w, token.trailing)
+		// https://stackoverflow.com/questions/13094690/how-many-spaces-for-tab-character-t
+
+	}
+}
+
+func printTrivia(w io.Writer, trivia []Trivia) {
+	for _, tr := range trivia {
+		if tr.kind == TriviaComment {
+			fmt.Fprintf(w, "%-10d %-10s\t %-10q\n", tr.line, triviaLookup[tr.kind], tr.text)
+		} else {
+			fmt.Fprintf(w, "%-10d %-10s\n", tr.line, triviaLookup[tr.kind])
+		}
+	}
+}
+
+// one token as written by `atomc lex`; this is the schema of its formats: the
+// keys of a json line and the columns of csv are these fields, in this order
+type TokenRecord struct {
+	File   string `json:"file"`
+	Line   uint   `json:"line"`
+	Col    uint   `json:"col"`
+	Type   string `json:"type"` // the name from constLookup
+	Lexeme string `json:"lexeme"`
+	// the decoded value: a number for CtInt and CtReal, the code point of
+	// a CtChar, a string for CtString, Id and TypeName, else null
+	Value interface{} `json:"value"`
+}
+
+func newTokenRecord(file string, t Token) TokenRecord {
+	r := TokenRecord{
+		File:   file,
+		Line:   t.line,
+		Col:    t.col,
+		Type:   constLookup[t.tokenType],
+		Lexeme: t.Lexeme(),
+	}
+	switch t.tokenType {
+	case Id, TypeName:
+		r.Value = t.Ident()
+	case CtInt:
+		r.Value = t.IntValue()
+	case CtReal:
+		r.Value = t.RealValue()
+	case CtChar:
+		r.Value = t.CharValue()
+	case CtString:
+		r.Value = t.StringValue()
+	}
+	return r
+}
+
+var tokenFormats = []string{"table", "json", "csv", "compact"}
+
+// writes the tokens in one of tokenFormats; json writes a TokenRecord per
+// line, csv a header and then a row per token, and compact a
+// `file:line:col Type lexeme` line per token
+func writeTokens(w io.Writer, file string, tokens []Token, format string) error {
+	switch format {
+	case "table":
+		printTokens(w, tokens)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		for _, t := range tokens {
+			if err := enc.Encode(newTokenRecord(file, t)); err != nil {
+				return err
 			}
-			break
-		case 27:
-			if c == '\\' {
-				state = 26
-			} else if c == '"' {
-				return Token{
-					tokenType: CtString,
-					value:     tokenStr,
-					line:      *currLine,
-				}
-			} else if c == '\n' {
-				*currLine += 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			} else {
-				tokenStr += string(c)
-				state = 28
+		}
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"file", "line", "col", "type", "lexeme", "value"})
+		for _, t := range tokens {
+			r := newTokenRecord(file, t)
+			value := ""
+			if r.Value != nil {
+				value = fmt.Sprint(r.Value)
 			}
-			break
-		case 28:
-			if c == '\\' {
-				state = 26
-			} else if c == '"' {
-				return Token{
-					tokenType: CtString,
-					value:     tokenStr,
-					line:      *currLine,
-				}
-			} else if c == '\n' {
-				*currLine += 1
-				return Token{
-					tokenType: Error,
-					line:      *currLine,
-				}
-			} else {
-				tokenStr += string(c)
+			cw.Write([]string{r.File, fmt.Sprint(r.Line), fmt.Sprint(r.Col), r.Type, r.Lexeme, value})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "compact":
+		for _, t := range tokens {
+			r := newTokenRecord(file, t)
+			if _, err := fmt.Fprintf(w, "%s:%d:%d %s %s\n", r.File, r.Line, r.Col, r.Type, strconv.Quote(r.Lexeme)); err != nil {
+				return err
 			}
+		}
+	default:
+		return fmt.Errorf("unknown token format %q, want one of %s", format, strings.Join(tokenFormats, ", "))
+	}
+	return nil
+}
 
-			break
+// ---------------------- DOMAIN -------------------------------------
 
-		case 29:
-			if c == '\n' || c == '\r' || c == '\x00' {
-				state = 0
-			} else {
-			}
-			break
-		case 30:
-			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
-				state = 30
-				tokenStr += string(c)
-			} else {
-				*curPos -= 1
-				switch tokenStr {
-				case "break":
-					return Token{
-						tokenType: Break,
-						line:      *currLine,
-					}
-				case "char":
-					return Token{
-						tokenType: Char,
-						line:      *currLine,
-					}
+type TypeBase int
 
-				case "double":
-					return Token{
-						tokenType: Double,
-						line:      *currLine,
-					}
+const (
+	TbInt TypeBase = iota
+	TbDouble
+	TbChar
+	TbStruct
+	TbEnum
+	TbVoid
+)
 
-				case "else":
-					return Token{
-						tokenType: Else,
-						line:      *currLine,
-					}
+type Type struct {
+	tb TypeBase
+	s  *Symbol // the struct or enum definition, for TbStruct and TbEnum
+	n  int     // -1 if not an array, 0 for an array without size, else the number of elements
+}
 
-				case "for":
-					return Token{
-						tokenType: For,
-						line:      *currLine,
-					}
+type SymbolClass int
 
-				case "if":
-					return Token{
-						tokenType: If,
-						line:      *currLine,
-					}
+const (
+	ClsVar SymbolClass = iota
+	ClsFunc
+	ClsStruct
+	ClsEnum
+	ClsEnumConst
+	ClsTypedef
+)
 
-				case "int":
-					return Token{
-						tokenType: Int,
-						line:      *currLine,
-					}
+type Symbol struct {
+	name    string
+	cls     SymbolClass
+	t       Type
+	depth   int       // 0 for globals, incremented for each nested `{}`
+	members []*Symbol // for ClsStruct
+	val     int64     // for ClsEnumConst
+}
 
-				case "return":
-					return Token{
-						tokenType: Return,
-						line:      *currLine,
-					}
+var symbols []*Symbol
 
-				case "struct":
-					return Token{
-						tokenType: Struct,
-						line:      *currLine,
-					}
+// the symbols with each name, in the order they were added, so that
+// findSymbol does not scan the whole table
+var symbolsByName = map[string][]*Symbol{}
 
-				case "void":
-					return Token{
-						tokenType: Void,
-						line:      *currLine,
-					}
+var crtDepth int = 0
 
-				case "while":
-					return Token{
-						tokenType: While,
-						line:      *currLine,
-					}
+// the struct whose members are being parsed, nil outside struct declarations
+var crtStruct *Symbol
+
+func addSymbol(name string, cls SymbolClass, t Type) *Symbol {
+	s := &Symbol{
+		name:  name,
+		cls:   cls,
+		t:     t,
+		depth: crtDepth,
+	}
+	symbols = append(symbols, s)
+	symbolsByName[name] = append(symbolsByName[name], s)
+	return s
+}
+
+// the names of structs and enums, their tags, do not clash with the other
+// names, as in `typedef struct Pt Pt`
+func isTag(cls SymbolClass) bool {
+	return cls == ClsStruct || cls == ClsEnum
+}
+
+// the innermost visible symbol with the given name which is not a tag
+func findSymbol(name string) *Symbol {
+	same := symbolsByName[name]
+	for i := len(same) - 1; i >= 0; i-- {
+		if !isTag(same[i].cls) {
+			return same[i]
+		}
+	}
+	return nil
+}
+
+// the innermost visible struct or enum with the given name
+func findTag(name string) *Symbol {
+	same := symbolsByName[name]
+	for i := len(same) - 1; i >= 0; i-- {
+		if isTag(same[i].cls) {
+			return same[i]
+		}
+	}
+	return nil
+}
+
+// drops the symbols of the domains deeper than depth
+func deleteSymbolsAfter(depth int) {
+	i := len(symbols)
+	for i > 0 && symbols[i-1].depth > depth {
+		i--
+		// the symbols are dropped in the reverse order of addSymbol, so each
+		// is the last one with its name
+		name := symbols[i].name
+		symbolsByName[name] = symbolsByName[name][:len(symbolsByName[name])-1]
+	}
+	symbols = symbols[:i]
+}
+
+// ---------------------- CONST --------------------------------------
+
+// the width of int in bits, 32 or 64, set with -intbits
+var intBits uint = 32
+
+func intMin() int64 {
+	return -1 << (intBits - 1)
+}
 
+func intMax() int64 {
+	return 1<<(intBits-1) - 1
+}
+
+// wraps the result of an int operation around to the width of int, like the
+// program would at run time, and warns if the operation overflowed
+func wrapInt(opId int, v int64, overflow bool) int64 {
+	if intBits < 64 {
+		w := v << (64 - intBits) >> (64 - intBits)
+		if w != v {
+			overflow = true
+		}
+		v = w
+	}
+	if overflow {
+		tokenWarnAt(opId, "integer overflow in constant expression")
+	}
+	return v
+}
+
+// the result of an expression; constant expressions also carry their value
+type RetVal struct {
+	t       Type
+	isCtVal bool
+	ctInt   int64   // the value of int constants, the code point of char ones
+	ctReal  float64 // the value of double constants
+}
+
+// enums behave like int: they take part in arithmetic and convert to int
+func isScalar(t Type) bool {
+	return t.n < 0 && (t.tb == TbInt || t.tb == TbDouble || t.tb == TbChar || t.tb == TbEnum)
+}
+
+func isIntegral(t Type) bool {
+	return t.n < 0 && (t.tb == TbInt || t.tb == TbChar || t.tb == TbEnum)
+}
+
+func (rv *RetVal) setInt(v int64) {
+	*rv = RetVal{t: Type{tb: TbInt, n: -1}, isCtVal: true, ctInt: v}
+}
+
+func (rv *RetVal) setReal(v float64) {
+	*rv = RetVal{t: Type{tb: TbDouble, n: -1}, isCtVal: true, ctReal: v}
+}
+
+func (rv *RetVal) setNotCt() {
+	*rv = RetVal{}
+}
+
+func (rv *RetVal) real() float64 {
+	if rv.t.tb == TbDouble {
+		return rv.ctReal
+	}
+	return float64(rv.ctInt)
+}
+
+func (rv *RetVal) truth() bool {
+	if rv.t.tb == TbDouble {
+		return rv.ctReal != 0
+	}
+	return rv.ctInt != 0
+}
+
+// converts a scalar constant to the type t; it returns false if a double did
+// not fit in an int, in which case the value is saturated
+func (rv *RetVal) convert(t Type) bool {
+	switch t.tb {
+	case TbDouble:
+		rv.setReal(rv.real())
+	case TbInt:
+		if rv.t.tb == TbDouble {
+			d := rv.ctReal
+			if d != d || d < float64(intMin()) || d >= -float64(intMin()) {
+				switch {
+				case d > 0:
+					rv.setInt(intMax())
+				case d < 0:
+					rv.setInt(intMin())
 				default:
-					return Token{
-						tokenType: Id,
-						value:     tokenStr,
-						line:      *currLine,
-					}
+					rv.setInt(0)
 				}
+				return false
 			}
-		// CtChar
-		default:
-			// invalid state
-			return Token{
-				tokenType: Error,
-				line:      *currLine,
-			}
+			rv.setInt(int64(d))
+		} else {
+			rv.setInt(rv.ctInt)
 		}
+	case TbChar:
+		ok := rv.convert(Type{tb: TbInt, n: -1})
+		// a char holds a Unicode code point, so it is 32 bits wide
+		rv.ctInt = int64(rune(rv.ctInt))
+		rv.t.tb = TbChar
+		return ok
+	case TbEnum:
+		ok := rv.convert(Type{tb: TbInt, n: -1})
+		rv.t = t
+		return ok
 	}
+	return true
 }
 
-func getTokens(text *string) []Token {
-	var tokens []Token
+// folds `l op r` into l; opId is the position of the operator, for diagnostics
+func foldBinary(opId int, l *RetVal, r *RetVal) {
+	op := tokens[opId].tokenType
+	// like in C, the right operand of && and || is not evaluated when the left
+	// one decides the result, so there it need not be a constant
+	if (op == And || op == Or) && l.isCtVal && isScalar(l.t) && l.truth() == (op == Or) {
+		l.setInt(boolToInt(op == Or))
+		return
+	}
+	if !l.isCtVal || !r.isCtVal || !isScalar(l.t) || !isScalar(r.t) {
+		l.setNotCt()
+		return
+	}
+	switch op {
+	case And:
+		l.setInt(boolToInt(l.truth() && r.truth()))
+		return
+	case Or:
+		l.setInt(boolToInt(l.truth() || r.truth()))
+		return
+	}
+	if l.t.tb == TbDouble || r.t.tb == TbDouble {
+		a, b := l.real(), r.real()
+		switch op {
+		case Add:
+			l.setReal(a + b)
+		case Sub:
+			l.setReal(a - b)
+		case Mul:
+			l.setReal(a * b)
+		case Div:
+			// a double division by zero gives an infinity or NaN, as at run time
+			l.setReal(a / b)
+		case Less:
+			l.setInt(boolToInt(a < b))
+		case LessEq:
+			l.setInt(boolToInt(a <= b))
+		case Greater:
+			l.setInt(boolToInt(a > b))
+		case GreaterEq:
+			l.setInt(boolToInt(a >= b))
+		case Equal:
+			l.setInt(boolToInt(a == b))
+		case NotEq:
+			l.setInt(boolToInt(a != b))
+		}
+		return
+	}
+	// the int64 operations wrap around silently, so their overflow is checked
+	// here for 64 bit ints; smaller ints are checked by wrapInt
+	a, b := l.ctInt, r.ctInt
+	switch op {
+	case Add:
+		l.setInt(wrapInt(opId, a+b, (a > 0 && b > 0 && a+b < 0) || (a < 0 && b < 0 && a+b >= 0)))
+	case Sub:
+		l.setInt(wrapInt(opId, a-b, (a >= 0 && b < 0 && a-b < 0) || (a < 0 && b > 0 && a-b >= 0)))
+	case Mul:
+		l.setInt(wrapInt(opId, a*b, a != 0 && ((a*b)/a != b || (a == -1 && b == math.MinInt64))))
+	case Div:
+		// an int division by zero has no value, so the expression is not a
+		// constant; it is only an error where a constant is required
+		if b == 0 {
+			tokenWarnAt(opId, "division by zero")
+			l.setNotCt()
+			return
+		}
+		l.setInt(wrapInt(opId, a/b, a == math.MinInt64 && b == -1))
+	case Less:
+		l.setInt(boolToInt(a < b))
+	case LessEq:
+		l.setInt(boolToInt(a <= b))
+	case Greater:
+		l.setInt(boolToInt(a > b))
+	case GreaterEq:
+		l.setInt(boolToInt(a >= b))
+	case Equal:
+		l.setInt(boolToInt(a == b))
+	case NotEq:
+		l.setInt(boolToInt(a != b))
+	}
+}
 
-	var currLine uint = 1
-	var curPos uint = 0
+func foldUnary(opId int, rv *RetVal) {
+	if !rv.isCtVal || !isScalar(rv.t) {
+		rv.setNotCt()
+		return
+	}
+	switch tokens[opId].tokenType {
+	case Sub:
+		if rv.t.tb == TbDouble {
+			rv.setReal(-rv.ctReal)
+		} else {
+			rv.setInt(wrapInt(opId, -rv.ctInt, rv.ctInt == math.MinInt64))
+		}
+	case Not:
+		rv.setInt(boolToInt(!rv.truth()))
+	}
+}
 
-outer:
-	for {
-		t := getNextToken(text, &curPos, &currLine)
-		tokens = append(tokens, t)
-		switch t.tokenType {
-		case End:
-			break outer
-		default:
-			continue
+// folds `rv ? rv1 : rv2` into rv; like in C, the result has the common type of
+// the two branches, so `1 ? 2 : 0.5` is the double 2.0, and only the branch
+// taken needs to be a constant
+func foldCond(rv *RetVal, rv1 *RetVal, rv2 *RetVal) {
+	if !rv.isCtVal || !isScalar(rv.t) {
+		rv.setNotCt()
+		return
+	}
+	taken, other := rv2, rv1
+	if rv.truth() {
+		taken, other = rv1, rv2
+	}
+	if !taken.isCtVal || !isScalar(taken.t) {
+		rv.setNotCt()
+		return
+	}
+	t := Type{tb: TbInt, n: -1}
+	if taken.t.tb == TbDouble || (isScalar(other.t) && other.t.tb == TbDouble) {
+		t.tb = TbDouble
+	}
+	*rv = *taken
+	rv.convert(t)
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ---------------------- TRACE --------------------------------------
+
+// the parse tree recorded by `atomc parse --trace`: every rule invocation,
+// including the ones which matched nothing or were backtracked, and the tokens
+// consumed
+type NodeStatus int
+
+const (
+	NodeOk          NodeStatus = iota
+	NodeFailed                 // the rule returned false
+	NodeBacktracked            // the rule matched, but the caller reset currTokenId before its end
+	NodeError                  // the rule was running when a syntax error stopped the parser
+)
+
+var nodeStatusLookup = map[NodeStatus]string{
+	NodeOk:          "",
+	NodeFailed:      "failed",
+	NodeBacktracked: "backtracked",
+	NodeError:       "error",
+}
+
+type ParseNode struct {
+	rule       string // empty for a token
+	start, end int    // the tokens tokens[start:end] were consumed
+	status     NodeStatus
+	children   []*ParseNode
+}
+
+// the rules being parsed, the root first; nil unless the parse is traced
+var traceStack []*ParseNode
+
+// called when the traced parse ends, even by an error
+var traceDone func()
+
+// records the invocation of a rule in the parse tree; each rule starts with
+//
+//	defer traceRule("name")(&ok)
+func traceRule(rule string) func(ok *bool) {
+	if traceStack == nil {
+		return func(*bool) {}
+	}
+	n := &ParseNode{rule: rule, start: currTokenId}
+	parent := traceStack[len(traceStack)-1]
+	parent.children = append(parent.children, n)
+	traceStack = append(traceStack, n)
+	return func(ok *bool) {
+		n.end = currTokenId
+		if !*ok {
+			n.status = NodeFailed
 		}
+		markBacktracked(n)
+		traceStack = traceStack[:len(traceStack)-1]
 	}
+}
 
-	return tokens
+func traceToken(id int) {
+	if traceStack == nil {
+		return
+	}
+	parent := traceStack[len(traceStack)-1]
+	parent.children = append(parent.children, &ParseNode{start: id, end: id + 1})
 }
 
-func printTokens(tokens []Token) {
-	fmt.Printf("%-10s %-10s \t %-10s\n", "line", "token", "value")
-	fmt.Printf("%s\n", strings.Repeat("-", 30))
-	for _, token := range tokens {
-		if token.value == nil {
-			fmt.Printf("%-10d %-10s\n", token.line, constLookup[token.tokenType])
-			continue
+// a child whose tokens reach past the start of a later child, or past the end
+// of n, was thrown away by a reset of currTokenId
+func markBacktracked(n *ParseNode) {
+	next := n.end
+	for i := len(n.children) - 1; i >= 0; i-- {
+		c := n.children[i]
+		if c.status == NodeOk && c.end > next {
+			c.status = NodeBacktracked
 		}
-		if token.tokenType == CtChar {
-			if char, ok := token.value.(uint8); ok {
-				fmt.Printf("%-10d %-10s\t %-10s\n", token.line, constLookup[token.tokenType], string(rune(int(char))))
-			}
-			continue
+		if c.start < next {
+			next = c.start
 		}
+	}
+}
 
-		if token.tokenType == CtReal {
-			if nr, ok := token.value.(float64); ok {
-				fmt.Printf("%-10d %-10s\t %-10f\n", token.line, constLookup[token.tokenType], nr)
-			}
-			continue
+// the parse tree as an indented outline; an ε marks the rules which matched
+// nothing, as the *1 rules do at the end of a list
+func writeTraceText(w io.Writer, n *ParseNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if n.rule == "" {
+		t := tokens[n.start]
+		fmt.Fprintf(w, "%s%s %s %d:%d", indent, constLookup[t.tokenType], strconv.Quote(t.Lexeme()), t.line, t.col)
+	} else {
+		fmt.Fprintf(w, "%s%s", indent, n.rule)
+		if n.start == n.end && n.status == NodeOk {
+			fmt.Fprintf(w, " ε")
 		}
-		fmt.Printf("%-10d %-10s\t %-10v\n", token.line, constLookup[token.tokenType], token.value)
-		// https://stackoverflow.com/questions/13094690/how-many-spaces-for-tab-character-t
+	}
+	if n.status != NodeOk {
+		fmt.Fprintf(w, " (%s)", nodeStatusLookup[n.status])
+	}
+	fmt.Fprintln(w)
+	for _, c := range n.children {
+		writeTraceText(w, c, depth+1)
+	}
+}
 
+// the parse tree as a Graphviz graph; the rules and tokens which did not end
+// up in the parse are dashed
+func writeTraceDot(w io.Writer, root *ParseNode) {
+	fmt.Fprintln(w, "digraph parse {")
+	fmt.Fprintln(w, "\tnode [fontname=\"monospace\"];")
+	id := 0
+	var walk func(n *ParseNode) int
+	walk = func(n *ParseNode) int {
+		me := id
+		id++
+		var label, attrs string
+		if n.rule == "" {
+			t := tokens[n.start]
+			label = constLookup[t.tokenType] + "\n" + t.Lexeme()
+			attrs = ", shape=box"
+		} else {
+			label = n.rule
+			if n.start == n.end && n.status == NodeOk {
+				label += " → ε"
+			}
+		}
+		if n.status != NodeOk {
+			label += "\n(" + nodeStatusLookup[n.status] + ")"
+			attrs += ", style=dashed, color=gray"
+		}
+		fmt.Fprintf(w, "\tn%d [label=%s%s];\n", me, strconv.Quote(label), attrs)
+		for _, c := range n.children {
+			fmt.Fprintf(w, "\tn%d -> n%d;\n", me, walk(c))
+		}
+		return me
 	}
+	walk(root)
+	fmt.Fprintln(w, "}")
 }
 
 // ---------------------- ANSIN --------------------------------------
@@ -866,37 +1231,143 @@ var tokens []Token
 
 var currTokenId int = 0
 
+// the parser lexes the source on demand, see fetchTokens
+var lexer *Lexer
+
+// lexes tokens until tokens[id] exists or the End token is reached
+//
+// this is the feedback from the parser to the lexer needed by typedef: an Id
+// which names a typedef visible at the point where it is read becomes a
+// TypeName token. The declarator of a typedef is followed by `[`, `,` or `;`,
+// so the symbol is always added before its name can be read again.
+func fetchTokens(id int) {
+	for len(tokens) <= id {
+		if len(tokens) > 0 && tokens[len(tokens)-1].tokenType == End {
+			return
+		}
+		t := lexer.getNextToken()
+		if t.tokenType == Id {
+			if s := findSymbol(t.Ident()); s != nil && s.cls == ClsTypedef {
+				t.tokenType = TypeName
+			}
+		}
+		tokens = append(tokens, t)
+	}
+}
+
+// the parser stops at the first syntax error; the tests replace these to
+// catch the error instead of exiting
+var parseErrOut io.Writer = os.Stdout
+var parseExit = os.Exit
+
 func tokenErr(msg string) {
-	if tokens[currTokenId].value != nil {
-		fmt.Printf("error in line %d at token %s: %s, found %#v\n", tokens[currTokenId].line,constLookup[tokens[currTokenId].tokenType], msg, tokens[currTokenId].value)
+	tokenErrAt(currTokenId, msg)
+}
+
+func tokenWarnAt(id int, msg string) {
+	fetchTokens(id)
+	fmt.Fprintf(parseErrOut, "warning in line %d at token %s: %s\n", tokens[id].line, constLookup[tokens[id].tokenType], msg)
+}
+
+func tokenErrAt(id int, msg string) {
+	fetchTokens(id)
+	if tokens[id].hasValue() {
+		fmt.Fprintf(parseErrOut, "error in line %d at token %s: %s, found %s\n", tokens[id].line, constLookup[tokens[id].tokenType], msg, tokens[id].Lexeme())
 	} else {
-		fmt.Printf("error in line %d at token %s: %s\n", tokens[currTokenId].line,constLookup[tokens[currTokenId].tokenType], msg)
+		fmt.Fprintf(parseErrOut, "error in line %d at token %s: %s\n", tokens[id].line, constLookup[tokens[id].tokenType], msg)
 	}
-	os.Exit(1)
+	if traceStack != nil {
+		for _, n := range traceStack {
+			n.status = NodeError
+			n.end = currTokenId
+		}
+		traceDone()
+	}
+	parseExit(1)
 }
 
 // consuma token-ul si mergi mai departe
 func consume(code TokenType) bool {
+	fetchTokens(currTokenId)
 	if tokens[currTokenId].tokenType == code {
+		traceToken(currTokenId)
 		currTokenId += 1
 		return true
 	}
 	return false
 }
 
-func unit() bool {
-	for {
-		startId := currTokenId
-		if declStruct() || func() bool {
-			currTokenId = startId
-			return declFunc()
-		}() || func() bool {
-			currTokenId = startId
-			return declVar()
-		}() {
+// consumes an identifier where only a name can appear, as after `struct` or
+// `.`; there it is a name even if the lexer made it a TypeName because a
+// typedef with the same name is visible
+func consumeName() bool {
+	return consume(Id) || consume(TypeName)
+}
 
-		} else {
-			break;
+func isName(tokenType TokenType) bool {
+	return tokenType == Id || tokenType == TypeName
+}
+
+// the type of the token k positions after the current one
+func peek(k int) TokenType {
+	fetchTokens(currTokenId + k)
+	if currTokenId+k >= len(tokens) {
+		return End
+	}
+	return tokens[currTokenId+k].tokenType
+}
+
+// tells if the tokens from currTokenId+k start an enum declaration, rather
+// than `enum Id` used as a type
+func startsDeclEnum(k int) bool {
+	return peek(k) == Enum && (peek(k+1) == Lacc || isName(peek(k+1)) && peek(k+2) == Lacc)
+}
+
+// the number of tokens of the typeBase starting at currTokenId+k, 0 if none
+func typeBaseLen(k int) int {
+	switch peek(k) {
+	case Int, Double, Char, TypeName:
+		return 1
+	case Struct, Enum:
+		if isName(peek(k + 1)) {
+			return 2
+		}
+	}
+	return 0
+}
+
+// unit: ( declEnum | declTypedef | declStruct | declFunc | declVar )* END
+// the alternative is chosen by looking ahead at most 4 tokens, so that no
+// declaration is parsed twice: a function is `void`, or a typeBase followed by
+// `*` or by `Id (`
+func unit() (ok bool) {
+	defer traceRule("unit")(&ok)
+	for {
+		if startsDeclEnum(0) {
+			declEnum()
+			continue
+		}
+		switch peek(0) {
+		case Typedef:
+			declTypedef()
+			continue
+		case Struct:
+			if isName(peek(1)) && peek(2) == Lacc {
+				declStruct()
+				continue
+			}
+		case Void:
+			declFunc()
+			continue
+		}
+		n := typeBaseLen(0)
+		if n > 0 && (peek(n) == Mul || isName(peek(n)) && peek(n+1) == Lpar) {
+			declFunc()
+			continue
+		}
+		// declVar reports a malformed typeBase, if there is one
+		if !declVar() {
+			break
 		}
 	}
 	if consume(End) {
@@ -905,11 +1376,15 @@ func unit() bool {
 	}
 	return false
 }
-func declStruct() bool {
+func declStruct() (ok bool) {
+	defer traceRule("declStruct")(&ok)
 
 	if consume(Struct) {
-		if consume(Id) {
+		if consumeName() {
+			name := tokens[currTokenId-1].Ident()
 			if consume(Lacc) {
+				crtStruct = addSymbol(name, ClsStruct, Type{tb: TbStruct, n: -1})
+				crtStruct.t.s = crtStruct
 				for {
 					if declVar() {
 
@@ -917,6 +1392,7 @@ func declStruct() bool {
 						break
 					}
 				}
+				crtStruct = nil
 				if consume(Racc) {
 					if consume(Semicolon) {
 						return true
@@ -934,15 +1410,146 @@ func declStruct() bool {
 
 	return false
 }
-func declVar() bool {
-	startId := currTokenId
-	if typeBase() {
-		if consume(Id) {
-			arrayDecl()
+
+// declEnum: ENUM ID? LACC enumerator ( COMMA enumerator )* COMMA? RACC SEMICOLON
+func declEnum() (ok bool) {
+	defer traceRule("declEnum")(&ok)
+	if consume(Enum) {
+		var enum *Symbol
+		if consumeName() {
+			name := tokens[currTokenId-1].Ident()
+			// declEnum is only tried when startsDeclEnum saw the `{`
+			if !consume(Lacc) {
+				tokenErr("expected `{` after the enum name")
+			}
+			enum = addSymbol(name, ClsEnum, Type{tb: TbEnum, n: -1})
+			enum.t.s = enum
+		} else if !consume(Lacc) {
+			tokenErr("expected identifier or `{` after enum")
+		}
+		t := Type{tb: TbEnum, s: enum, n: -1}
+		var val int64 = 0
+		if enumerator(t, &val) {
+			for {
+				if consume(Comma) {
+					if enumerator(t, &val) {
+
+					} else {
+						break
+					}
+				} else {
+					break
+				}
+			}
+		} else {
+			tokenErr("expected enumerator")
+		}
+		if consume(Racc) {
+			if consume(Semicolon) {
+				return true
+			} else {
+				tokenErr("expected `;` at the end of the enum")
+			}
+		} else {
+			tokenErr("expected `}` at the end of the enum")
+		}
+	}
+	return false
+}
+
+// enumerator: ID ( ASSIGN exprCond )?
+// val is the value of this enumerator unless it is given explicitly, and is
+// advanced to the value of the next one
+func enumerator(t Type, val *int64) (ok bool) {
+	defer traceRule("enumerator")(&ok)
+	if consume(Id) {
+		nameId := currTokenId - 1
+		name := tokens[nameId].Ident()
+		if consume(Assign) {
+			startId := currTokenId
+			var rv RetVal
+			if exprCond(&rv) {
+				if !rv.isCtVal || !isIntegral(rv.t) {
+					tokenErrAt(startId, "enumerator value must be an integer constant expression")
+				}
+				if rv.ctInt < intMin() || rv.ctInt > intMax() {
+					tokenErrAt(startId, fmt.Sprintf("enumerator value %d does not fit in an int", rv.ctInt))
+				}
+				*val = rv.ctInt
+			} else {
+				tokenErr("expected value after `=`")
+			}
+		} else if *val-1 == intMax() {
+			// the enumerator before had the value intMax, and val went past it
+			// (for 64 bit ints, wrapping around to intMin)
+			tokenErrAt(nameId, fmt.Sprintf("enumerator value %d+1 does not fit in an int", intMax()))
+		}
+		s := addSymbol(name, ClsEnumConst, t)
+		s.val = *val
+		*val += 1
+		return true
+	}
+	return false
+}
+
+// declTypedef: TYPEDEF typeBase ID arrayDecl? ( COMMA ID arrayDecl? )* SEMICOLON
+func declTypedef() (ok bool) {
+	defer traceRule("declTypedef")(&ok)
+	if consume(Typedef) {
+		var t Type
+		if typeBase(&t) {
+			if typedefName(t) {
+				for {
+					if consume(Comma) {
+						if typedefName(t) {
+
+						} else {
+							tokenErr("expected identifier")
+						}
+					} else {
+						break
+					}
+				}
+				if consume(Semicolon) {
+					return true
+				} else {
+					tokenErr("expected `;` at the end of the typedef")
+				}
+			} else {
+				tokenErr("expected identifier")
+			}
+		} else {
+			tokenErr("expected type after typedef")
+		}
+	}
+	return false
+}
+func typedefName(t Type) (ok bool) {
+	defer traceRule("typedefName")(&ok)
+	if consumeName() {
+		nameId := currTokenId - 1
+		// a typedef may be shadowed in an inner domain, but not redefined
+		if s := findSymbol(tokens[nameId].Ident()); s != nil && s.cls == ClsTypedef && s.depth == crtDepth {
+			tokenErrAt(nameId, "redefinition of typedef")
+		}
+		arrayDecl(&t)
+		if t.n == 0 {
+			tokenErrAt(nameId, "array size missing")
+		}
+		addSymbol(tokens[nameId].Ident(), ClsTypedef, t)
+		return true
+	}
+	return false
+}
+func declVar() (ok bool) {
+	defer traceRule("declVar")(&ok)
+	var t Type
+	if typeBase(&t) {
+		if varDef(t) {
 			for {
 				if consume(Comma) {
-					if consume(Id) {
-						arrayDecl()
+					if varDef(t) {
+
 					} else {
 						tokenErr("expected identifier")
 					}
@@ -959,28 +1566,199 @@ func declVar() bool {
 			tokenErr("expected identifier")
 		}
 	}
-	currTokenId = startId
 	return false
 }
-func typeBase() bool {
 
-	if consume(Int) || consume(Double) || consume(Char) {
+// varDef: ID arrayDecl? ( ASSIGN initializer )?
+func varDef(t Type) (ok bool) {
+	defer traceRule("varDef")(&ok)
+	if consumeName() {
+		nameId := currTokenId - 1
+		arrayDecl(&t)
+		if consume(Assign) {
+			if crtStruct != nil {
+				tokenErr("struct members cannot have initializers")
+			}
+			initId := currTokenId
+			var rv RetVal
+			n := 0
+			if initializer(&rv, &n) {
+				// like in C, a string literal may drop its '\0' to fit the array
+				isString := tokens[initId].tokenType == CtString && currTokenId == initId+1
+				isList := tokens[initId].tokenType == Lacc
+				switch {
+				case t.n == 0:
+					t.n = n
+				case t.n > 0:
+					if n > t.n && !(isString && n == t.n+1) {
+						tokenErrAt(initId, "too many initializers for array")
+					}
+				case t.tb == TbStruct:
+					if isList && n > len(t.s.members) {
+						tokenErrAt(initId, "too many initializers for struct")
+					}
+				default:
+					if isList && n > 1 {
+						tokenErrAt(initId, "too many initializers for scalar")
+					}
+				}
+				if crtDepth == 0 && !rv.isCtVal {
+					tokenErrAt(initId, "initializer of a global variable must be a constant expression")
+				}
+			} else {
+				tokenErr("expected initializer after `=`")
+			}
+		}
+		if t.n == 0 {
+			tokenErrAt(nameId, "array size missing")
+		}
+		name := tokens[nameId].Ident()
+		if crtStruct != nil {
+			crtStruct.members = append(crtStruct.members, &Symbol{name: name, cls: ClsVar, t: t, depth: crtDepth})
+		} else {
+			addSymbol(name, ClsVar, t)
+		}
+		return true
+	}
+	return false
+}
+
+// initializer: expr | LACC initializer ( COMMA initializer )* COMMA? RACC
+// n is set to the number of elements the initializer provides and rv.isCtVal
+// tells whether all of them are constant
+func initializer(rv *RetVal, n *int) (ok bool) {
+	defer traceRule("initializer")(&ok)
+	if consume(Lacc) {
+		var elem RetVal
+		var elemN int
+		if initializer(&elem, &elemN) {
+			isCtVal := elem.isCtVal
+			*n = 1
+			for {
+				if consume(Comma) {
+					if initializer(&elem, &elemN) {
+						isCtVal = isCtVal && elem.isCtVal
+						*n += 1
+					} else {
+						break
+					}
+				} else {
+					break
+				}
+			}
+			if consume(Racc) {
+				rv.setNotCt()
+				rv.isCtVal = isCtVal
+				return true
+			} else {
+				tokenErr("expected `}` at the end of the initializer list")
+			}
+		} else {
+			tokenErr("expected initializer inside `{}`")
+		}
+	}
+	if expr(rv) {
+		// a string literal provides its characters and the terminating '\0'
+		if rv.t.n > 0 {
+			*n = rv.t.n
+		} else {
+			*n = 1
+		}
+		return true
+	}
+	return false
+}
+
+// tells if the token at id can begin a typeBase
+func startsTypeName(id int) bool {
+	fetchTokens(id)
+	if id >= len(tokens) {
+		return false
+	}
+	switch tokens[id].tokenType {
+	case Int, Double, Char, Struct, Enum, TypeName:
+		return true
+	}
+	return false
+}
+func typeBase(t *Type) (ok bool) {
+	defer traceRule("typeBase")(&ok)
+
+	*t = Type{n: -1}
+	if consume(Int) {
+		t.tb = TbInt
+		return true
+	}
+	if consume(Double) {
+		t.tb = TbDouble
+		return true
+	}
+	if consume(Char) {
+		t.tb = TbChar
 		return true
 	}
 	if consume(Struct) {
-		if consume(Id) {
+		if consumeName() {
+			s := findTag(tokens[currTokenId-1].Ident())
+			if s == nil || s.cls != ClsStruct {
+				tokenErrAt(currTokenId-1, "undefined struct")
+			}
+			t.tb = TbStruct
+			t.s = s
 			return true
 		} else {
 			tokenErr("expected identifier after struct")
 		}
-	} 
+	}
+	if consume(TypeName) {
+		*t = findSymbol(tokens[currTokenId-1].Ident()).t
+		return true
+	}
+	if consume(Enum) {
+		if consumeName() {
+			s := findTag(tokens[currTokenId-1].Ident())
+			if s == nil || s.cls != ClsEnum {
+				tokenErrAt(currTokenId-1, "undefined enum")
+			}
+			t.tb = TbEnum
+			t.s = s
+			return true
+		} else {
+			tokenErr("expected identifier after enum")
+		}
+	}
 
 	return false
 }
-func arrayDecl() bool {
+
+// arrayDecl: LBRACKET expr? RBRACKET
+// the size must be a positive integer constant expression and is stored in t.n
+func arrayDecl(t *Type) (ok bool) {
+	defer traceRule("arrayDecl")(&ok)
 	if consume(Lbracket) {
-		expr()
+		if t.n >= 0 {
+			tokenErrAt(currTokenId-1, "arrays of arrays are not supported")
+		}
+		startId := currTokenId
+		var rv RetVal
+		if expr(&rv) {
+			if !rv.isCtVal {
+				tokenErrAt(startId, "array size must be a constant expression")
+			}
+			if !isIntegral(rv.t) {
+				tokenErrAt(startId, "array size must be an integer")
+			}
+			if rv.ctInt <= 0 {
+				tokenErrAt(startId, fmt.Sprintf("array size must be positive, got %d", rv.ctInt))
+			}
+			t.n = int(rv.ctInt)
+		} else {
+			t.n = 0
+		}
 		if consume(Rbracket) {
+			if peek(0) == Lbracket {
+				tokenErr("arrays of arrays are not supported")
+			}
 			return true
 		} else {
 			tokenErr("expected `]`")
@@ -988,26 +1766,38 @@ func arrayDecl() bool {
 	}
 	return false
 }
-func typeName() bool {
-	if typeBase() {
-		arrayDecl()
+func typeName(t *Type) (ok bool) {
+	defer traceRule("typeName")(&ok)
+	if typeBase(t) {
+		arrayDecl(t)
 		return true
 	}
 	return false
 }
 
-func declFunc() bool {
+// declFunc: ( typeBase MUL? | VOID ) ID LPAR ( funcArg ( COMMA funcArg )* )? RPAR stmCompound
+// unit only calls it when the lookahead shows a function
+func declFunc() (ok bool) {
+	defer traceRule("declFunc")(&ok)
 	startId := currTokenId
+	var t Type
 	if func() bool {
-		if typeBase() {
-			consume(Mul)
+		if typeBase(&t) {
+			if consume(Mul) {
+				t.n = 0
+			}
 			return true
 		} else {
 			return false
 		}
 	}() || consume(Void) {
-		if consume(Id) {
+		if tokens[startId].tokenType == Void {
+			t.tb = TbVoid
+		}
+		if consumeName() {
+			name := tokens[currTokenId-1].Ident()
 			if consume(Lpar) {
+				addSymbol(name, ClsFunc, t)
 				if funcArg() {
 					for {
 						if consume(Comma) {
@@ -1030,18 +1820,26 @@ func declFunc() bool {
 				} else {
 					tokenErr("expected `)` at the end of the argument list")
 				}
-			} 
+			} else {
+				tokenErr("expected `(` after the function name")
+			}
 		} else {
 			tokenErr("expected identifier")
 		}
 	}
-	currTokenId = startId
 	return false
 }
-func funcArg() bool {
-	if typeBase() {
-		if consume(Id) {
-			arrayDecl()
+
+// the arguments belong to the domain of the function body
+func funcArg() (ok bool) {
+	defer traceRule("funcArg")(&ok)
+	var t Type
+	if typeBase(&t) {
+		if consumeName() {
+			name := tokens[currTokenId-1].Ident()
+			arrayDecl(&t)
+			s := addSymbol(name, ClsVar, t)
+			s.depth = crtDepth + 1
 			return true
 		} else {
 			tokenErr("expected identifier")
@@ -1049,14 +1847,16 @@ func funcArg() bool {
 	}
 	return false
 }
-func stm() bool {
+func stm() (ok bool) {
+	defer traceRule("stm")(&ok)
+	var rv RetVal
 
 	if stmCompound() {
 		return true
 	}
 	if consume(If) {
 		if consume(Lpar) {
-			if expr() {
+			if expr(&rv) {
 				if consume(Rpar) {
 					if stm() {
 						if consume(Else) {
@@ -1082,7 +1882,7 @@ func stm() bool {
 	}
 	if consume(While) {
 		if consume(Lpar) {
-			if expr() {
+			if expr(&rv) {
 				if consume(Rpar) {
 					if stm() {
 						return true
@@ -1101,11 +1901,11 @@ func stm() bool {
 	}
 	if consume(For) {
 		if consume(Lpar) {
-			expr()
+			expr(&rv)
 			if consume(Semicolon) {
-				expr()
+				expr(&rv)
 				if consume(Semicolon) {
-					expr()
+					expr(&rv)
 					if consume(Rpar) {
 						if stm() {
 							return true
@@ -1133,14 +1933,14 @@ func stm() bool {
 		}
 	}
 	if consume(Return) {
-		expr()
+		expr(&rv)
 		if consume(Semicolon) {
 			return true
 		} else {
 			tokenErr("expected `;` after return")
 		}
 	}
-	if expr() {
+	if expr(&rv) {
 		if consume(Semicolon) {
 			return true
 		} else {
@@ -1153,21 +1953,27 @@ func stm() bool {
 
 	return false
 }
-func stmCompound() bool {
-	
+func stmCompound() (ok bool) {
+	defer traceRule("stmCompound")(&ok)
+
 	if consume(Lacc) {
+		crtDepth += 1
 		for {
-			startId := currTokenId
-			if declVar() || func() bool {
-				currTokenId = startId
-				return stm()
-			}() {
-
-			} else {
+			// an expression cannot start with a type name, so a declaration
+			// is told apart from a statement by its first token
+			if startsDeclEnum(0) {
+				declEnum()
+			} else if peek(0) == Typedef {
+				declTypedef()
+			} else if startsTypeName(currTokenId) {
+				declVar()
+			} else if !stm() {
 				break
 			}
 		}
 		if consume(Racc) {
+			crtDepth -= 1
+			deleteSymbolsAfter(crtDepth)
 			return true
 		} else {
 			tokenErr("expected `}` at the end of the statement")
@@ -1177,41 +1983,81 @@ func stmCompound() bool {
 	return false
 }
 
-func expr() bool {
-	return exprAssign()
+func expr(rv *RetVal) (ok bool) {
+	defer traceRule("expr")(&ok)
+	return exprAssign(rv)
 }
-func exprAssign() bool {
+
+// the tokens tokens[lastUnaryStart:lastUnaryEnd] of the exprUnary parsed last
+var lastUnaryStart, lastUnaryEnd int = -1, -1
+
+// exprAssign: exprUnary ASSIGN exprAssign | exprCond
+// an exprUnary is an exprCond too, so the left side is parsed once, as an
+// exprCond, and is the left side of an assignment if it was just an exprUnary;
+// parsing it again would take twice as long for each level of parentheses
+func exprAssign(rv *RetVal) (ok bool) {
+	defer traceRule("exprAssign")(&ok)
 	startId := currTokenId
-	if exprUnary() {
-		if consume(Assign) {
-			if exprAssign() {
+	if exprCond(rv) {
+		if lastUnaryStart == startId && lastUnaryEnd == currTokenId && consume(Assign) {
+			if exprAssign(rv) {
+				rv.setNotCt()
 				return true
 			} else {
 				tokenErr("missing right side of operand in assignment")
 			}
 		}
+		return true
 	}
-	currTokenId = startId
-	if exprOr() {
+	return false
+}
+
+// exprCond: exprOr ( QUESTION expr COLON exprCond )?
+// the `:` branch recurses into exprCond, so `a ? b : c ? d : e` groups to the right
+func exprCond(rv *RetVal) (ok bool) {
+	defer traceRule("exprCond")(&ok)
+	if exprOr(rv) {
+		if consume(Question) {
+			var rv1, rv2 RetVal
+			if expr(&rv1) {
+				if consume(Colon) {
+					if exprCond(&rv2) {
+						foldCond(rv, &rv1, &rv2)
+						return true
+					} else {
+						tokenErr("expected expression after `:` in conditional expression")
+					}
+				} else {
+					tokenErr("expected `:` in conditional expression")
+				}
+			} else {
+				tokenErr("expected expression after `?` in conditional expression")
+			}
+		}
 		return true
 	}
 	return false
 }
-func exprOr() bool {
+func exprOr(rv *RetVal) (ok bool) {
+	defer traceRule("exprOr")(&ok)
 
-	if exprAnd() {
-		if exprOr1() {
+	if exprAnd(rv) {
+		if exprOr1(rv) {
 			return true
-		} 
+		}
 	}
 	return false
 }
 
-func exprOr1() bool {
+func exprOr1(rv *RetVal) (ok bool) {
+	defer traceRule("exprOr1")(&ok)
 
+	opId := currTokenId
 	if consume(Or) {
-		if exprAnd() {
-			if exprOr1() {
+		var right RetVal
+		if exprAnd(&right) {
+			foldBinary(opId, rv, &right)
+			if exprOr1(rv) {
 
 			}
 		} else {
@@ -1220,18 +2066,23 @@ func exprOr1() bool {
 	}
 	return true
 }
-func exprAnd() bool {
-	if exprEq() {
-		if exprAnd1() {
+func exprAnd(rv *RetVal) (ok bool) {
+	defer traceRule("exprAnd")(&ok)
+	if exprEq(rv) {
+		if exprAnd1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprAnd1() bool {
+func exprAnd1(rv *RetVal) (ok bool) {
+	defer traceRule("exprAnd1")(&ok)
+	opId := currTokenId
 	if consume(And) {
-		if exprEq() {
-			if exprAnd1() {
+		var right RetVal
+		if exprEq(&right) {
+			foldBinary(opId, rv, &right)
+			if exprAnd1(rv) {
 
 			}
 		} else {
@@ -1240,18 +2091,23 @@ func exprAnd1() bool {
 	}
 	return true
 }
-func exprEq() bool {
-	if exprRel() {
-		if exprEq1() {
+func exprEq(rv *RetVal) (ok bool) {
+	defer traceRule("exprEq")(&ok)
+	if exprRel(rv) {
+		if exprEq1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprEq1() bool {
+func exprEq1(rv *RetVal) (ok bool) {
+	defer traceRule("exprEq1")(&ok)
+	opId := currTokenId
 	if consume(Equal) || consume(NotEq) {
-		if exprRel() {
-			if exprEq1() {
+		var right RetVal
+		if exprRel(&right) {
+			foldBinary(opId, rv, &right)
+			if exprEq1(rv) {
 				return true
 			}
 		} else {
@@ -1260,18 +2116,23 @@ func exprEq1() bool {
 	}
 	return true
 }
-func exprRel() bool {
-	if exprAdd() {
-		if exprRel1() {
+func exprRel(rv *RetVal) (ok bool) {
+	defer traceRule("exprRel")(&ok)
+	if exprAdd(rv) {
+		if exprRel1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprRel1() bool {
+func exprRel1(rv *RetVal) (ok bool) {
+	defer traceRule("exprRel1")(&ok)
+	opId := currTokenId
 	if consume(Less) || consume(LessEq) || consume(Greater) || consume(GreaterEq) {
-		if exprAdd() {
-			if exprRel1() {
+		var right RetVal
+		if exprAdd(&right) {
+			foldBinary(opId, rv, &right)
+			if exprRel1(rv) {
 				return true
 			}
 		} else {
@@ -1280,18 +2141,23 @@ func exprRel1() bool {
 	}
 	return true
 }
-func exprAdd() bool {
-	if exprMul() {
-		if exprAdd1() {
+func exprAdd(rv *RetVal) (ok bool) {
+	defer traceRule("exprAdd")(&ok)
+	if exprMul(rv) {
+		if exprAdd1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprAdd1() bool {
+func exprAdd1(rv *RetVal) (ok bool) {
+	defer traceRule("exprAdd1")(&ok)
+	opId := currTokenId
 	if consume(Add) || consume(Sub) {
-		if exprMul() {
-			if exprAdd1() {
+		var right RetVal
+		if exprMul(&right) {
+			foldBinary(opId, rv, &right)
+			if exprAdd1(rv) {
 				return true
 			}
 		} else {
@@ -1300,18 +2166,23 @@ func exprAdd1() bool {
 	}
 	return true
 }
-func exprMul() bool {
-	if exprCast() {
-		if exprMul1() {
+func exprMul(rv *RetVal) (ok bool) {
+	defer traceRule("exprMul")(&ok)
+	if exprCast(rv) {
+		if exprMul1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprMul1() bool {
+func exprMul1(rv *RetVal) (ok bool) {
+	defer traceRule("exprMul1")(&ok)
+	opId := currTokenId
 	if consume(Mul) || consume(Div) {
-		if exprCast() {
-			if exprMul1() {
+		var right RetVal
+		if exprCast(&right) {
+			foldBinary(opId, rv, &right)
+			if exprMul1(rv) {
 				return true
 			}
 		} else {
@@ -1320,53 +2191,72 @@ func exprMul1() bool {
 	}
 	return true
 }
-func exprCast() bool {
+
+// a `(` which is not followed by a type name starts a parenthesized
+// expression, which exprPrimary handles
+func exprCast(rv *RetVal) (ok bool) {
+	defer traceRule("exprCast")(&ok)
 	startId := currTokenId
-	if consume(Lpar) {
-		if typeName() {
-			if consume(Rpar) {
-				if exprCast() {
-					return true
+	var t Type
+	if consume(Lpar) && typeName(&t) {
+		if consume(Rpar) {
+			if exprCast(rv) {
+				if rv.isCtVal && isScalar(rv.t) && isScalar(t) {
+					if !rv.convert(t) {
+						tokenWarnAt(startId, "the constant does not fit in the int it is converted to")
+					}
+				} else {
+					rv.setNotCt()
 				}
+				return true
 			} else {
-				tokenErr("expected `)` for casting")
+				tokenErr("expected expression after cast")
 			}
 		} else {
-			tokenErr("expected type name")
+			tokenErr("expected `)` for casting")
 		}
 	}
-	if exprUnary() {
+	currTokenId = startId
+	if exprUnary(rv) {
 		return true
 	}
-	currTokenId = startId
 	return false
 }
-func exprUnary() bool {
+func exprUnary(rv *RetVal) (ok bool) {
+	defer traceRule("exprUnary")(&ok)
+	opId := currTokenId
 	if consume(Sub) || consume(Not) {
-		if exprUnary() {
+		if exprUnary(rv) {
+			foldUnary(opId, rv)
+			lastUnaryStart, lastUnaryEnd = opId, currTokenId
 			return true
 		}
 	}
 
-	if exprPostfix() {
+	if exprPostfix(rv) {
+		lastUnaryStart, lastUnaryEnd = opId, currTokenId
 		return true
 	}
 	return false
 }
-func exprPostfix() bool {
-	if exprPrimary() {
-		if exprPostfix1() {
+func exprPostfix(rv *RetVal) (ok bool) {
+	defer traceRule("exprPostfix")(&ok)
+	if exprPrimary(rv) {
+		if exprPostfix1(rv) {
 			return true
 		}
 	}
 	return false
 }
-func exprPostfix1() bool {
+func exprPostfix1(rv *RetVal) (ok bool) {
+	defer traceRule("exprPostfix1")(&ok)
 
 	if consume(Lbracket) {
-		if expr() {
+		var idx RetVal
+		if expr(&idx) {
 			if consume(Rbracket) {
-				if exprPostfix1() {
+				rv.setNotCt()
+				if exprPostfix1(rv) {
 					return true
 				}
 			} else {
@@ -1377,8 +2267,9 @@ func exprPostfix1() bool {
 		}
 	}
 	if consume(Dot) {
-		if consume(Id) {
-			if exprPostfix1() {
+		if consumeName() {
+			rv.setNotCt()
+			if exprPostfix1(rv) {
 				return true
 			}
 		} else {
@@ -1387,14 +2278,21 @@ func exprPostfix1() bool {
 	}
 	return true
 }
-func exprPrimary() bool {
+func exprPrimary(rv *RetVal) (ok bool) {
+	defer traceRule("exprPrimary")(&ok)
 
 	if consume(Id) {
+		rv.setNotCt()
+		if s := findSymbol(tokens[currTokenId-1].Ident()); s != nil && s.cls == ClsEnumConst {
+			*rv = RetVal{t: s.t, isCtVal: true, ctInt: s.val}
+			return true
+		}
 		if consume(Lpar) {
-			if expr() {
+			var arg RetVal
+			if expr(&arg) {
 				for {
 					if consume(Comma) {
-						if expr() {
+						if expr(&arg) {
 
 						} else {
 							tokenErr("expected expression after `,`")
@@ -1412,15 +2310,35 @@ func exprPrimary() bool {
 		}
 		return true
 	}
-	if consume(CtInt) || consume(CtReal) || consume(CtChar) || consume(CtString) {
+	if consume(CtInt) {
+		rv.setInt(tokens[currTokenId-1].IntValue())
+		return true
+	}
+	if consume(CtReal) {
+		rv.setReal(tokens[currTokenId-1].RealValue())
+		return true
+	}
+	if consume(CtChar) {
+		rv.setInt(int64(tokens[currTokenId-1].CharValue()))
+		rv.t.tb = TbChar
+		return true
+	}
+	if consume(CtString) {
+		str := tokens[currTokenId-1].StringValue()
+		*rv = RetVal{t: Type{tb: TbChar, n: len(str) + 1}, isCtVal: true}
 		return true
 	}
 	if consume(Lpar) {
-		if expr() {
+		// `(` followed by a type name is a cast, which exprCast parses
+		if startsTypeName(currTokenId) {
+			currTokenId -= 1
+			return false
+		}
+		if expr(rv) {
 			if consume(Rpar) {
 				return true
 			} else {
-				tokenErr("expected `(` after expression")
+				tokenErr("expected `)` after expression")
 			}
 		} else {
 			tokenErr("expected expression after `(`")
@@ -1429,30 +2347,152 @@ func exprPrimary() bool {
 	return false
 }
 
-func ansin() {
+func ansin(text *string) {
+	lexer = newLexer(*text)
 	if unit() {
 	} else {
 		tokenErr("top level error")
 	}
 }
 
+// the constant folding only knows ints of 32 and 64 bits
+func checkIntBits() {
+	if intBits != 32 && intBits != 64 {
+		fmt.Fprintln(os.Stderr, "-intbits must be 32 or 64")
+		os.Exit(1)
+	}
+}
+
+// atomc lex [--format=f] file: only runs the lexer and writes the tokens; the
+// diagnostics go to stderr, so that stdout can be fed to other tools
+func lexCommand(args []string) {
+	fs := flag.NewFlagSet("lex", flag.ExitOnError)
+	format := fs.String("format", "table", "the output format: "+strings.Join(tokenFormats, ", "))
+	fs.UintVar(&intBits, "intbits", 32, "the width of int in bits, 32 or 64")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s lex [options] file\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	checkIntBits()
+	content, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lx := newLexer(string(content))
+	lexTokens := getTokens(lx)
+	for _, d := range lx.diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+	if err := writeTokens(os.Stdout, fs.Arg(0), lexTokens, *format); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if len(lx.diagnostics) > 0 {
+		os.Exit(1)
+	}
+}
+
+// atomc parse --trace=text|dot file: parses the file and writes the parse
+// tree of every rule invocation
+func parseCommand(args []string) {
+	fs := flag.NewFlagSet("parse", flag.ExitOnError)
+	format := fs.String("trace", "text", "the format of the parse tree: text or dot")
+	fs.UintVar(&intBits, "intbits", 32, "the width of int in bits, 32 or 64")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s parse [options] file\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if *format != "text" && *format != "dot" {
+		fmt.Fprintf(os.Stderr, "unknown trace format %q, want text or dot\n", *format)
+		os.Exit(1)
+	}
+	checkIntBits()
+	content, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	text := string(content)
+	lx := newLexer(text)
+	getTokens(lx)
+	for _, d := range lx.diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+
+	// stdout only gets the trace, so that `atomc parse --trace=dot f.c | dot`
+	// works even when f.c has an error
+	parseErrOut = os.Stderr
+	root := &ParseNode{rule: "program"}
+	traceStack = []*ParseNode{root}
+	traceDone = func() {
+		root.end = currTokenId
+		traceStack = nil
+		if *format == "dot" {
+			writeTraceDot(os.Stdout, root)
+		} else {
+			writeTraceText(os.Stdout, root, 0)
+		}
+	}
+	ansin(&text)
+	traceDone()
+	if len(lx.diagnostics) > 0 {
+		os.Exit(1)
+	}
+}
+
 func main() {
 
-	if len(os.Args) < 2 {
-		fmt.Printf("usage: %s [options] file\n", os.Args[0])
+	if len(os.Args) > 1 && os.Args[1] == "lex" {
+		lexCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "parse" {
+		parseCommand(os.Args[2:])
+		return
+	}
+
+	flag.UintVar(&intBits, "intbits", 32, "the width of int in bits, 32 or 64")
+	trivia := flag.Bool("trivia", false, "print the comments and blank lines with the tokens")
+	flag.Usage = func() {
+		fmt.Printf("usage: %s [options] file\n       %s lex [options] file\n       %s parse [options] file\n", os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
 		os.Exit(1)
 	}
+	checkIntBits()
 
-	content, err := ioutil.ReadFile(os.Args[1])
+	content, err := ioutil.ReadFile(flag.Arg(0))
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	text := string(content)
 
-	tokens = getTokens(&text)
 	// Lexical
-	printTokens(tokens)
+	lx := newLexer(text)
+	lx.keepTrivia = *trivia
+	lexTokens := getTokens(lx)
+	for _, d := range lx.diagnostics {
+		fmt.Println(d)
+	}
+	printTokens(os.Stdout, lexTokens)
 	// Sintactic
-	ansin()
+	ansin(&text)
+	if len(lx.diagnostics) > 0 {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetParser() {
+	tokens, currTokenId, symbols, crtDepth = nil, 0, nil, 0
+	symbolsByName = map[string][]*Symbol{}
+}
+
+type parseError string
+
+// parses text with a fresh parser state and returns its warnings and syntax
+// error, "" if there are none
+func parse(text string) (err string) {
+	resetParser()
+	var out strings.Builder
+	parseErrOut = &out
+	parseExit = func(int) { panic(parseError(out.String())) }
+	defer func() {
+		parseErrOut, parseExit = os.Stdout, os.Exit
+		if r := recover(); r != nil {
+			e, ok := r.(parseError)
+			if !ok {
+				panic(r)
+			}
+			err = strings.TrimSpace(string(e))
+		}
+	}()
+	ansin(&text)
+	return strings.TrimSpace(out.String())
+}
+
+// a program and the warnings and syntax error parsing it must give, "" for none
+type parseTest struct {
+	text string
+	err  string
+}
+
+func checkParseErrors(t *testing.T, tests []parseTest) {
+	t.Helper()
+	for _, test := range tests {
+		if err := parse(test.text); err != test.err {
+			t.Errorf("%q: got the output %q, want %q", test.text, err, test.err)
+		}
+	}
+}
+
+// parses text with a fresh parser state, recording the parse tree
+func traceParse(text string) *ParseNode {
+	resetParser()
+	root := &ParseNode{rule: "program"}
+	traceStack = []*ParseNode{root}
+	ansin(&text)
+	root.end = currTokenId
+	traceStack = nil
+	return root
+}
+
+func TestParseTrace(t *testing.T) {
+	var b strings.Builder
+	writeTraceText(&b, traceParse("int x;"), 0)
+	want := `program
+  unit
+    declVar
+      typeBase
+        Int "int" 1:1
+      varDef
+        Id "x" 1:5
+        arrayDecl (failed)
+      Semicolon ";" 1:6
+    declVar (failed)
+      typeBase (failed)
+    End "" 1:7
+`
+	if b.String() != want {
+		t.Errorf("got the trace\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	writeTraceText(&b, traceParse("int f(){ return (1)+2; }"), 0)
+	// exprCast takes `(` for the start of a cast until no type name follows
+	for _, line := range []string{"Lpar \"(\" 1:17 (backtracked)\n", "typeName (failed)\n", "exprAdd1\n", "Add \"+\" 1:20\n", "exprAdd1 ε\n", "exprMul1 ε\n"} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("the trace has no line %q:\n%s", line, b.String())
+		}
+	}
+}
+
+// a program of n functions and 2n globals, for the parser benchmark
+func generateProgram(n int) string {
+	var b strings.Builder
+	b.WriteString("struct Pt{ int x, y; };\ntypedef struct Pt Point;\nenum Dir {N, E, S, W};\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "int g%d[%d];\nPoint p%d;\n", i, i%10+1, i)
+		fmt.Fprintf(&b, "double f%d(int a, Point q)\n{\n\tint i;\n\tenum Dir d;\n", i)
+		fmt.Fprintf(&b, "\tfor (i = 0; i < %d; i = i + 1) g%d[i] = a * (i + q.x) - 1;\n", i%10+1, i)
+		fmt.Fprintf(&b, "\tif (a > 0 && d != W) return (double)a / 2; else return p%d.y;\n}\n", i)
+	}
+	return b.String()
+}
+
+// an assignment whose right side is nested in depth parentheses
+func generateNested(depth int) string {
+	return "int x;\nvoid f()\n{\n\tx = " + strings.Repeat("(", depth) + "x + 1" + strings.Repeat(")", depth) + ";\n}\n"
+}
+
+// the time per function should stay the same as the program grows, as every
+// declaration is parsed once, and the time per level of parentheses too, as
+// every expression is
+func BenchmarkParse(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		text := generateProgram(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				resetParser()
+				ansin(&text)
+			}
+			b.ReportMetric(float64(time.Since(start).Nanoseconds())/float64(b.N*n), "ns/func")
+		})
+	}
+	for _, depth := range []int{10, 100, 1000} {
+		text := generateNested(depth)
+		b.Run(fmt.Sprint("depth", depth), func(b *testing.B) {
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				resetParser()
+				ansin(&text)
+			}
+			b.ReportMetric(float64(time.Since(start).Nanoseconds())/float64(b.N*depth), "ns/level")
+		})
+	}
+}
+
+func TestParseTypedefNames(t *testing.T) {
+	tests := []parseTest{
+		// struct and enum names do not clash with typedefs
+		{"struct Pt{int x;}; typedef struct Pt Pt; struct Pt q; Pt r;", ""},
+		{"typedef int E; enum E {A}; enum E e; E i;", ""},
+		// a name after `.` or of a member, or of a function
+		{"typedef int T; struct S{int T;}; void f(){ struct S s; s.T = 1; }", ""},
+		{"typedef int T; int T(){ return 0; }", ""},
+		// an inner typedef or variable shadows an outer typedef
+		{"typedef int T; void f(){ typedef double T; T d; }", ""},
+		{"typedef int T; void f(){ T T; T = 1; }", ""},
+		{"typedef int T; typedef double T;", "error in line 1 at token TypeName: redefinition of typedef, found T"},
+		{"void f(){ typedef int T; typedef char T; }", "error in line 1 at token TypeName: redefinition of typedef, found T"},
+	}
+	checkParseErrors(t, tests)
+}
+
+func TestParseAssign(t *testing.T) {
+	tests := []parseTest{
+		{"int a, b[2]; void f(){ a = b[0] = -a; a = (a = 1) + ((a)); }", ""},
+		{"int a; void f(){ a + 1 = 2; }", "error in line 1 at token Assign: expected `;` at the end of the expression"},
+		{"int a; void f(){ (a) = 2; }", ""},
+		{"int a; void f(){ a = ; }", "error in line 1 at token Semicolon: missing right side of operand in assignment"},
+		// each level of parentheses is parsed once
+		{generateNested(200), ""},
+	}
+	checkParseErrors(t, tests)
+}
+
+func TestParseArraySizes(t *testing.T) {
+	errors := []parseTest{
+		{"int n; int a[n];", "error in line 1 at token Id: array size must be a constant expression, found n"},
+		{"int a[0];", "error in line 1 at token CtInt: array size must be positive, got 0, found 0"},
+		{"int a[-2];", "error in line 1 at token Sub: array size must be positive, got -2"},
+		{"int a[2.5];", "error in line 1 at token CtReal: array size must be an integer, found 2.5"},
+		{"int a[1][2];", "error in line 1 at token Lbracket: arrays of arrays are not supported"},
+		{"typedef int V[2]; V m[3];", "error in line 1 at token Lbracket: arrays of arrays are not supported"},
+		{"int a[];", "error in line 1 at token Id: array size missing, found a"},
+		{"int a[2] = {1, 2, 3};", "error in line 1 at token Lacc: too many initializers for array"},
+		{"int n; int a = n;", "error in line 1 at token Id: initializer of a global variable must be a constant expression, found n"},
+		// an int division by zero is not a constant where it is evaluated
+		{"int a[1/0];", "warning in line 1 at token Div: division by zero\nerror in line 1 at token CtInt: array size must be a constant expression, found 1"},
+		{"int a[1 ? 1/0 : 2];", "warning in line 1 at token Div: division by zero\nerror in line 1 at token CtInt: array size must be a constant expression, found 1"},
+		{"enum {A = 1/0};", "warning in line 1 at token Div: division by zero\nerror in line 1 at token CtInt: enumerator value must be an integer constant expression, found 1"},
+		{"int g = 1/0;", "warning in line 1 at token Div: division by zero\nerror in line 1 at token CtInt: initializer of a global variable must be a constant expression, found 1"},
+		{"int x; void f(){ x = 0 ? 1/0 : 1; }", "warning in line 1 at token Div: division by zero"},
+		{"double d; void f(){ d = 1.0/0; }", ""},
+	}
+	checkParseErrors(t, errors)
+
+	text := `int squares[(int)3.5 + 1];
+char s[] = "abc";
+double d[] = {1, 2.5, 3};
+enum {K = 2 * 3};
+int b[K - 1 ? K : 1], c[K / 4];
+int e[0 && 1/0 ? 1 : 2], o[1 || 1/0];
+int n;`
+	// the divisions by zero are not evaluated, but are still warned about
+	want := "warning in line 6 at token Div: division by zero\nwarning in line 6 at token Div: division by zero"
+	if out := parse(text); out != want {
+		t.Fatalf("got the output %q, want %q", out, want)
+	}
+	sizes := map[string]int{"squares": 4, "s": 4, "d": 3, "b": 6, "c": 1, "e": 2, "o": 1, "n": -1}
+	for name, n := range sizes {
+		if s := findSymbol(name); s == nil || s.t.n != n {
+			t.Errorf("%s: got the symbol %+v, want the size %d", name, s, n)
+		}
+	}
+}
+
+// unit and stmCompound choose between the declarations by their first tokens,
+// which the alternatives share up to the name
+func TestParseDeclarationChoice(t *testing.T) {
+	tests := []parseTest{
+		{`struct S{int x;};
+struct S a[2], b;
+struct S f(struct S p){ struct S l; l = p; return l; }
+enum E {A, B};
+enum E e = B;
+enum E g(enum E p){ enum E l; enum {C}; l = C; return p; }
+typedef struct S Pt;
+Pt h(Pt p){ return p; }
+Pt q;
+double *k(){ }
+void m(){ typedef int T; T t; enum F {D}; enum F u; struct S s; t = D; }`, ""},
+		// the first tokens commit to a function
+		{"void x;", "error in line 1 at token Semicolon: expected `(` after the function name"},
+		{"int *p;", "error in line 1 at token Semicolon: expected `(` after the function name"},
+		// and to a declaration inside a block
+		{"void f(){ int 1; }", "error in line 1 at token CtInt: expected identifier, found 1"},
+		{"struct 1;", "error in line 1 at token CtInt: expected identifier after struct, found 1"},
+	}
+	checkParseErrors(t, tests)
+}
+
+func TestParseEnumValues(t *testing.T) {
+	checkParseErrors(t, []parseTest{
+		{"enum {A = 2147483647, B};", "error in line 1 at token Id: enumerator value 2147483647+1 does not fit in an int, found B"},
+		{"enum {A = 2147483647, B = 0};", ""},
+	})
+
+	if err := parse("enum {A = -2147483647 - 1, B, C = 'a', D};"); err != "" {
+		t.Fatal(err)
+	}
+	values := map[string]int64{"A": -2147483648, "B": -2147483647, "C": 'a', "D": 'b'}
+	for name, val := range values {
+		if s := findSymbol(name); s == nil || s.val != val {
+			t.Errorf("%s: got the symbol %+v, want the value %d", name, s, val)
+		}
+	}
+}
+
+func TestParseInitializers(t *testing.T) {
+	checkParseErrors(t, []parseTest{
+		{`struct P{int x; int y;}; struct P p = {1, 2}; int s = {1}; char c[2] = "ab", d[] = "ab";
+void f(){ struct P q = p; }`, ""},
+		{"struct P{int x;}; struct P p = {1, 2, 3};", "error in line 1 at token Lacc: too many initializers for struct"},
+		{"int s = {1, 2, 3};", "error in line 1 at token Lacc: too many initializers for scalar"},
+		{`char c[2] = "abc";`, `error in line 1 at token CtString: too many initializers for array, found "abc"`},
+		{`char c[2] = {'a', 'b', 0};`, "error in line 1 at token Lacc: too many initializers for array"},
+	})
+}
+
+func TestParseConditional(t *testing.T) {
+	checkParseErrors(t, []parseTest{
+		// the branches have a common type, so this size is the double 2.0
+		{"int a[1 ? 2 : 0.5];", "error in line 1 at token CtInt: array size must be an integer, found 1"},
+		{"int x; void f(){ x = 1 ? 2 ; }", "error in line 1 at token Semicolon: expected `:` in conditional expression"},
+		{"int x; void f(){ x = 1 ? : 2; }", "error in line 1 at token Colon: expected expression after `?` in conditional expression"},
+		{"int x; void f(){ x = 1 ? 2 : ; }", "error in line 1 at token Semicolon: expected expression after `:` in conditional expression"},
+	})
+
+	// ?: groups to the right, so `1 ? 4 : 0 ? 2 : 3` is not `(1 ? 4 : 0) ? 2 : 3`
+	if err := parse("int b[0 ? 1 : 0 ? 2 : 3], c[1 ? 4 : 0 ? 2 : 3];"); err != "" {
+		t.Fatal(err)
+	}
+	sizes := map[string]int{"b": 3, "c": 4}
+	for name, n := range sizes {
+		if s := findSymbol(name); s == nil || s.t.n != n {
+			t.Errorf("%s: got the symbol %+v, want the size %d", name, s, n)
+		}
+	}
+}
+
+func TestParseIntOverflow(t *testing.T) {
+	defer func(bits uint) { intBits = bits }(intBits)
+	intBits = 32
+	checkParseErrors(t, []parseTest{
+		{"int x; void f(){ x = 2147483647 + 1; }", "warning in line 1 at token Add: integer overflow in constant expression"},
+		{"int x; void f(){ x = -(-2147483647 - 1); }", "warning in line 1 at token Sub: integer overflow in constant expression"},
+		{"int x; void f(){ x = (int)1e10; }", "warning in line 1 at token Lpar: the constant does not fit in the int it is converted to"},
+		{"int x; void f(){ x = 2147483646 + 1; x = (int)2e9; }", ""},
+	})
+
+	intBits = 64
+	checkParseErrors(t, []parseTest{
+		{"int x; void f(){ x = 2147483647 + 1; x = (int)1e10; }", ""},
+		{"int x; void f(){ x = (-9223372036854775807 - 1) / -1; }", "warning in line 1 at token Div: integer overflow in constant expression"},
+		{"int x; void f(){ x = 9223372036854775807 * 2; }", "warning in line 1 at token Mul: integer overflow in constant expression"},
+	})
+}
@@ -0,0 +1,22 @@
+int max(int a, int b)
+{
+	return a > b ? a : b;
+}
+
+int sign(int x)
+{
+	return x < 0 ? -1 : x == 0 ? 0 : 1;
+}
+
+void main()
+{
+	int		x, y;
+	double	d;
+	put_s("x=");
+	x=get_i();
+	y=x >= 0 ? x : -x;
+	d=x > 10 ? 1.5 : x;
+	put_i(max(x, y));
+	put_i(sign(x) > 0 ? y : sign(x) < 0 ? -y : 0);
+	put_d(d);
+}
@@ -0,0 +1,26 @@
+struct Pt{
+	int x,y;
+	};
+
+int		n = 0, limit = 10;
+double	v[3] = {1.0, 2.0, 3.0};
+char	s[] = "abc";
+struct Pt	origin = {0, 0};
+struct Pt	corners[2] = {{0, 0}, {10, 10},};
+
+int sum(int count)
+{
+	int		i, total = 0;
+	for(i = 0; i < count; i = i + 1)
+		total = total + i;
+	return total;
+}
+
+void main()
+{
+	int		k = sum(limit);
+	double	avg = k / 2.0, w[2] = {avg, v[1]};
+	put_i(k);
+	put_d(w[0]);
+	put_s(s);
+}
@@ -0,0 +1,24 @@
+struct Pt{
+	int x,y;
+	};
+
+char	digits[] = "0123456789";
+int		squares[(int)3.5 + 1] = {0, 1, 4, 9};
+double	half[10 / 4 * 2 + (1 ? 1 : 0)];
+struct Pt	grid[('z' - 'a' + 1) * 2];
+
+int sum(int v[], int n)
+{
+	int		i, s, buf[-(-4) > 2 ? 8 : 4];
+	s = 0;
+	for(i = 0; i < n; i = i + 1)
+		s = s + v[i];
+	return s * (int)(1.0 / 2 + 1);
+}
+
+void main()
+{
+	put_i(sum(squares, 4));
+	put_c(digits[(1 + 2) * 3]);
+	put_d((double)squares[3] / 2);
+}
@@ -0,0 +1,20 @@
+enum Color { RED, GREEN = 5, BLUE };
+enum { SIZE = BLUE * 2, LAST = SIZE - 1, };
+
+enum Color	palette[SIZE];
+int		hits[BLUE - GREEN + 1] = {0, 0};
+
+int brightness(enum Color c)
+{
+	enum Level { LOW, HIGH = 10 };
+	return c == RED ? HIGH : c + LOW;
+}
+
+void main()
+{
+	enum Color	c;
+	int		v[(int)RED + LAST];
+	c = BLUE;
+	palette[0] = GREEN;
+	put_i(brightness(c) + (enum Color)2);
+}
@@ -0,0 +1,35 @@
+struct Pt{
+	int x,y;
+	};
+
+typedef struct Pt Point;
+typedef int Matrix[10], Count;
+typedef double Real;
+
+Point	origin = {0, 0};
+Matrix	m;
+
+Real scale(Point p, Real factor)
+{
+	typedef char Letter;
+	Letter	c;
+	c = (Letter)(p.x + 'a');
+	return (Real)p.x * factor;
+}
+
+Count total(Matrix v, Count n)
+{
+	Count	i, s;
+	s = 0;
+	for(i = 0; i < n; i = i + 1)
+		s = s + v[i];
+	return s;
+}
+
+void main()
+{
+	Real	Count;
+	Count = scale(origin, 2.5);
+	put_d(Count);
+	put_i(total(m, 10));
+}
@@ -0,0 +1,19 @@
+// siruri si caractere UTF-8
+char	salut[] = "Bună ziua, țară!";
+char	litere[] = {'ă', 'â', 'î', 'ș', 'ț'};
+char	scapate[] = "\u0218tefan \x41";
+
+int lungime(char s[])
+{
+	int i;
+	i = 0;
+	while (s[i] != '\0') i = i + 1;
+	return i;
+}
+
+void main()
+{
+	char c;
+	c = 'Ș';
+	if (c == 'Ș') lungime(salut);
+}